@@ -0,0 +1,101 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/SamuelBanksTech/Go-Postgresql-Query-Builder/pqbHelpers"
+)
+
+// Array is a slice bound with a cast to the array type, needed where postgres can not work out the type of the arg
+// Usage "xxx.WhereArrayOverlaps(`ids`, pqb.Array{Values: ids, Type: `bigint`})" binds $1::bigint[]
+type Array struct {
+	Values interface{}
+	Type   string
+}
+
+// WhereArrayContains matches rows where the array column contains every element of the slice (postgres @>)
+// The slice (e.g. []string, []int64) is bound as a single postgres array literal arg, so it works with any driver
+// values can also be an Array to cast the arg to the array type
+// Usage "xxx.From(`myschema.mytable`).WhereArrayContains(`tags`, []string{"go", "sql"})"
+func (s *Sqlbuilder) WhereArrayContains(column string, values interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereArray(column, `@>`, values)
+}
+
+// WhereArrayContainedBy matches rows where every element of the array column is within the slice (postgres <@)
+// Usage "xxx.From(`myschema.mytable`).WhereArrayContainedBy(`permissions`, []string{"read", "write"})"
+func (s *Sqlbuilder) WhereArrayContainedBy(column string, values interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereArray(column, `<@`, values)
+}
+
+// WhereArrayOverlaps matches rows where the array column has at least one element in common with the slice (postgres &&)
+// Usage "xxx.From(`myschema.mytable`).WhereArrayOverlaps(`tags`, []string{"go", "sql"})"
+func (s *Sqlbuilder) WhereArrayOverlaps(column string, values interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereArray(column, `&&`, values)
+}
+
+// WhereArrayLength compares the number of elements in the array column, empty arrays have a length of 0
+// operator can be "<", "<=", ">", ">=", "=" or "!=", anything else is recorded for Err
+// Usage "xxx.From(`myschema.mytable`).WhereArrayLength(`tags`, `>=`, 2)"
+func (s *Sqlbuilder) WhereArrayLength(column string, operator string, length int) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.WhereRaw(`cardinality(` + s.formatColumn(column) + `) ` + s.formatComparison(operator) + ` ` + s.storeArg(length))
+}
+
+// whereArray adds an array comparison, anything that is not a slice or array is recorded for Err and the query will not
+// build rather than the filter being left out
+func (s *Sqlbuilder) whereArray(column string, operator string, values interface{}) *Sqlbuilder {
+	cast := ``
+
+	if a, ok := values.(Array); ok {
+		values = a.Values
+
+		if a.Type != `` {
+			if !typeName.MatchString(a.Type) {
+				s.setErr(errors.New("array type: " + strconv.Quote(a.Type) + " is not a valid type name"))
+			}
+
+			cast = `::` + a.Type + `[]`
+		}
+	}
+
+	if values == nil {
+		s.setErr(errors.New("array: " + strconv.Quote(column) + " expected a slice or array, got nil"))
+		return s
+	}
+
+	switch reflect.TypeOf(values).Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		s.setErr(errors.New("array: " + strconv.Quote(column) + " expected a slice or array, got " + reflect.TypeOf(values).String()))
+		return s
+	}
+
+	literal, err := pqbHelpers.ArrayLiteral(values)
+	if err != nil {
+		s.setErr(err)
+	}
+
+	return s.WhereRaw(s.formatColumn(column) + ` ` + operator + ` ` + s.storeArg(literal) + cast)
+}
@@ -0,0 +1,101 @@
+package pqb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlbuilder_WhereArray(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	tags := []string{`go`, `sql`}
+	ids := []int64{1, 2}
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereArrayContains(`tags`, tags).
+		WhereArrayContainedBy(`permissions`, tags).
+		WhereArrayOverlaps(`mytable.ids`, ids).
+		WhereArrayLength(`tags`, `>=`, 2).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "tags" @> $1 AND "permissions" <@ $2 AND "mytable"."ids" && $3 AND cardinality("tags") >= $4`
+	wantArgs := []interface{}{`{"go","sql"}`, `{"go","sql"}`, `{1,2}`, 2}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_WhereArray_not_a_slice(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(s *Sqlbuilder) *Sqlbuilder
+	}{
+		{`string`, func(s *Sqlbuilder) *Sqlbuilder { return s.WhereArrayContains(`tags`, `go`) }},
+		{`nil`, func(s *Sqlbuilder) *Sqlbuilder { return s.WhereArrayOverlaps(`tags`, nil) }},
+		{`nil in an Array`, func(s *Sqlbuilder) *Sqlbuilder { return s.WhereArrayContainedBy(`tags`, Array{Type: `text`}) }},
+	}
+
+	for _, tt := range tests {
+		var sqlb Sqlbuilder
+
+		gotSql, _ := tt.build(sqlb.From(`myschema.mytable`)).Build()
+
+		if gotSql != `` || sqlb.Err() == nil {
+			t.Errorf("%v: expected an error: got %v", tt.name, gotSql)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereArray_literal(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	word := `a"b`
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereArrayOverlaps(`ids`, Array{Values: []int{1, 2}, Type: `bigint`}).
+		WhereArrayContains(`words`, []*string{&word, nil}).
+		WhereArrayContains(`grid`, [][]float64{{1.5, 2}, {3, 4}}).
+		WhereArrayContains(`flags`, Array{Values: []bool{true, false}}).
+		WhereArrayContains(`notes`, []string{`x,y`, `back\slash`, `NULL`}).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "ids" && $1::bigint[] AND "words" @> $2 AND "grid" @> $3 AND "flags" @> $4 AND "notes" @> $5`
+	wantArgs := []interface{}{`{1,2}`, `{"a\"b",NULL}`, `{{1.5,2},{3,4}}`, `{t,f}`, `{"x,y","back\\slash","NULL"}`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_WhereArray_invalid_type(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		WhereArrayContains(`ids`, Array{Values: []int{1}, Type: `int); DROP TABLE x; --`}).
+		Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for an invalid array type: got %v", gotSql)
+	}
+}
+
+func TestSqlbuilder_WhereArrayLength_invalid_operator(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		WhereArrayLength(`tags`, `>= 0; DROP TABLE x; --`, 2).
+		Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for an invalid operator: got %v", gotSql)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+// Clone returns a copy of the builder and everything added to it so far, the copy and the original can then be changed
+// separately, e.g. to branch a base query into a list and a count query
+// Usage "list := base.Clone().Limit(10).Offset(20)"
+func (s *Sqlbuilder) Clone() *Sqlbuilder {
+	c := *s
+	c.queryArgs = append([]interface{}(nil), s.queryArgs...)
+
+	if s.AllowedColumns != nil {
+		c.AllowedColumns = make(ColumnSet, len(s.AllowedColumns))
+		for k, v := range s.AllowedColumns {
+			c.AllowedColumns[k] = v
+		}
+	}
+
+	return &c
+}
+
+// mutate returns the builder a method should change, a clone when the builder is Immutable otherwise the builder itself
+func (s *Sqlbuilder) mutate() *Sqlbuilder {
+	if s.Immutable {
+		return s.Clone()
+	}
+
+	return s
+}
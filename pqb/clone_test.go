@@ -0,0 +1,129 @@
+package pqb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlbuilder_Clone(t *testing.T) {
+	var base Sqlbuilder
+	base.From(`myschema.mytable`).Where(`active`, `=`, `true`)
+
+	list := base.Clone().Where(`age`, `>`, `20`).Limit(10)
+	count := base.Clone()
+
+	gotSql, gotArgs := list.Build()
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "age" > $2 LIMIT 10`
+	wantArgs := []interface{}{`true`, `20`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	gotSql, gotArgs = count.Count()
+	wantSql = `SELECT COUNT(*) AS rowcount FROM (SELECT * FROM "myschema"."mytable" WHERE "active" = $1) AS rowdata`
+	wantArgs = []interface{}{`true`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	if _, baseArgs := base.Build(); !reflect.DeepEqual(baseArgs, wantArgs) {
+		t.Errorf("base changed by its clone \ngot %v \nwanted %v", baseArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_Immutable(t *testing.T) {
+	base := (&Sqlbuilder{Immutable: true}).From(`myschema.mytable`).Where(`active`, `=`, `true`)
+
+	admins := base.Where(`role`, `=`, `admin`)
+	users := base.Where(`role`, `=`, `user`).OrderBy(`name`, `ASC`)
+
+	tests := []struct {
+		name     string
+		builder  *Sqlbuilder
+		wantSql  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "base",
+			builder:  base,
+			wantSql:  `SELECT * FROM "myschema"."mytable" WHERE "active" = $1`,
+			wantArgs: []interface{}{`true`},
+		},
+		{
+			name:     "admins",
+			builder:  admins,
+			wantSql:  `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "role" = $2`,
+			wantArgs: []interface{}{`true`, `admin`},
+		},
+		{
+			name:     "users",
+			builder:  users,
+			wantSql:  `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "role" = $2 ORDER BY "name" ASC`,
+			wantArgs: []interface{}{`true`, `user`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSql, gotArgs := tt.builder.Build()
+
+			if gotSql != tt.wantSql {
+				t.Errorf("got %v \nwanted %v", gotSql, tt.wantSql)
+			}
+
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSqlbuilder_Immutable_BuildUpdate(t *testing.T) {
+	base := (&Sqlbuilder{Immutable: true}).Where(`id`, `=`, `1`)
+
+	gotSql, gotArgs, err := base.Set(`status`, `archived`).BuildUpdate(`myschema.mytable`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "status" = $2 WHERE "id" = $1 `
+	wantArgs := []interface{}{`1`, `archived`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	if base.whereStmt == `` || len(base.queryArgs) != 1 {
+		t.Errorf("base changed by BuildUpdate: %v %v", base.whereStmt, base.queryArgs)
+	}
+}
+
+func TestSqlbuilder_Build_pure(t *testing.T) {
+	sqlb := Sqlbuilder{Dialect: `MySQL`}
+	sqlb.From(`mytable`).Where(`id`, `=`, `1`)
+
+	first, _ := sqlb.Build()
+	second, _ := sqlb.Build()
+
+	if first != second {
+		t.Errorf("got %v \nwanted %v", second, first)
+	}
+
+	if sqlb.Dialect != `MySQL` {
+		t.Errorf("dialect changed to %v", sqlb.Dialect)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ColumnSet is an allowlist of the columns that can be filtered and sorted on, mapping the public field names used by
+// API clients to the real (optionally qualified) column names. An empty real name means the public name is the column
+// Usage "qb.AllowedColumns = pqb.ColumnSet{"name": "users.display_name", "created": "users.created_at", "id": ""}"
+type ColumnSet map[string]string
+
+// Column returns the real column for the public name and if it is allowed
+func (c ColumnSet) Column(name string) (string, bool) {
+	column, ok := c[name]
+	if ok && column == `` {
+		column = name
+	}
+
+	return column, ok
+}
+
+// formatColumn is formatSchema for the column of a filter or sort, when AllowedColumns is set the column must be one of its
+// public names and is swapped for the real column, anything else is recorded for Err and the query will not build
+func (s *Sqlbuilder) formatColumn(column string) string {
+	if s.AllowedColumns == nil {
+		return s.formatSchema(column)
+	}
+
+	realColumn, ok := s.AllowedColumns.Column(strings.TrimSpace(column))
+	if !ok {
+		s.setErr(errors.New("column: " + strconv.Quote(column) + " is not allowed"))
+		return s.formatSchema(`invalid`)
+	}
+
+	return s.formatSchema(realColumn)
+}
+
+// formatDirection only allows ASC or DESC optionally followed by NULLS FIRST or NULLS LAST, so a sort direction
+// can safely come from user input, anything else is recorded for Err and the query will not build
+func (s *Sqlbuilder) formatDirection(direction string) string {
+	direction = strings.Join(strings.Fields(strings.ToUpper(direction)), ` `)
+
+	switch direction {
+	case ``, `ASC`, `DESC`, `NULLS FIRST`, `NULLS LAST`, `ASC NULLS FIRST`, `ASC NULLS LAST`, `DESC NULLS FIRST`, `DESC NULLS LAST`:
+		return direction
+	default:
+		s.setErr(errors.New("direction: " + strconv.Quote(direction) + " is not allowed"))
+		return ``
+	}
+}
+
+// formatComparison only allows the plain comparison operators, so an operator can safely come from user input, anything
+// else is recorded for Err and the query will not build
+func (s *Sqlbuilder) formatComparison(operator string) string {
+	operator = strings.TrimSpace(operator)
+
+	switch operator {
+	case `<`, `<=`, `>`, `>=`, `=`, `!=`:
+		return operator
+	default:
+		s.setErr(errors.New("operator: " + strconv.Quote(operator) + " is not allowed"))
+		return ``
+	}
+}
@@ -0,0 +1,78 @@
+package pqb
+
+import (
+	"testing"
+
+	"github.com/SamuelBanksTech/Go-Postgresql-Query-Builder/pqbExpr"
+)
+
+func TestSqlbuilder_AllowedColumns(t *testing.T) {
+	sqlb := Sqlbuilder{AllowedColumns: ColumnSet{`name`: `users.display_name`, `id`: ``}}
+
+	gotSql, gotArgs := sqlb.From(`myschema.users`).
+		Where(`name`, `=`, `bob`).
+		WhereIn(`id`, []int{1, 2}).
+		OrderBy(`name`, `desc nulls last`).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."users" WHERE "users"."display_name" = $1 AND "id" IN ($2, $3) ORDER BY "users"."display_name" DESC NULLS LAST`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != 3 {
+		t.Errorf("argument slice length wrong: \ngot %v", gotArgs)
+	}
+
+	if sqlb.Err() != nil {
+		t.Errorf("unexpected error %v", sqlb.Err())
+	}
+}
+
+func TestSqlbuilder_AllowedColumns_rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(s *Sqlbuilder) *Sqlbuilder
+	}{
+		{`where`, func(s *Sqlbuilder) *Sqlbuilder { return s.Where(`password`, `=`, `x`) }},
+		{`order by`, func(s *Sqlbuilder) *Sqlbuilder { return s.OrderBy(`password`, `ASC`) }},
+		{`real column name`, func(s *Sqlbuilder) *Sqlbuilder { return s.Where(`users.display_name`, `=`, `x`) }},
+		{`like`, func(s *Sqlbuilder) *Sqlbuilder { return s.WhereILike(`email`, `x`, MatchContains) }},
+		{`direction`, func(s *Sqlbuilder) *Sqlbuilder { return s.OrderBy(`name`, `ASC; DROP TABLE users`) }},
+		{`where expr`, func(s *Sqlbuilder) *Sqlbuilder {
+			return s.WhereExpr(pqbExpr.Eq(pqbExpr.Col(`password`), pqbExpr.Val(1)))
+		}},
+		{`order by expr`, func(s *Sqlbuilder) *Sqlbuilder { return s.OrderByExpr(pqbExpr.Col(`secret`), `ASC`) }},
+	}
+
+	for _, tt := range tests {
+		sqlb := Sqlbuilder{AllowedColumns: ColumnSet{`name`: `users.display_name`}}
+
+		gotSql, _ := tt.build(sqlb.From(`myschema.users`)).Build()
+
+		if gotSql != `` {
+			t.Errorf("%v: got %v \nwanted an empty query", tt.name, gotSql)
+		}
+
+		if sqlb.Err() == nil {
+			t.Errorf("%v: expected an error", tt.name)
+		}
+	}
+}
+
+func TestSqlbuilder_AllowedColumns_expr(t *testing.T) {
+	sqlb := Sqlbuilder{AllowedColumns: ColumnSet{`name`: `users.display_name`}}
+
+	gotSql, _ := sqlb.From(`myschema.users`).
+		Select(`id`, `password`).
+		WhereExpr(pqbExpr.Eq(pqbExpr.Func(`lower`, pqbExpr.Col(`name`)), pqbExpr.Val(`bob`))).
+		OrderByExpr(pqbExpr.Col(`name`), `ASC`).
+		Build()
+
+	wantSql := `SELECT "id", "password" FROM "myschema"."users" WHERE (lower("users"."display_name") = $1) ORDER BY "users"."display_name" ASC`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Query is a compiled query, its sql is built once and it can not be changed so it is safe to share between goroutines
+type Query struct {
+	sql   string
+	args  []interface{}
+	names []string
+}
+
+// Compile builds the query once so it can be kept (e.g. in a package variable) and run many times, any named parameters
+// (see WhereNamed) are left to be given on each run with Args
+// Usage "q, err := xxx.From(`myschema.mytable`).WhereNamed(`id`, `=`, `id`).Compile()" then "args, err := q.Args(42)"
+func (s *Sqlbuilder) Compile() (*Query, error) {
+	sqlquery, args := s.build()
+	if sqlquery == `` {
+		if err := s.buildErr(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("sql build failed")
+	}
+
+	q := &Query{sql: sqlquery, args: args}
+	seen := make(map[string]bool)
+
+	for _, arg := range args {
+		if n, ok := arg.(namedArg); ok && !seen[n.name] {
+			seen[n.name] = true
+			q.names = append(q.names, n.name)
+		}
+	}
+
+	return q, nil
+}
+
+// SQL returns the compiled query
+func (q *Query) SQL() string {
+	return q.sql
+}
+
+// Params returns the names of the named parameters in the order their values are given to Args
+func (q *Query) Params() []string {
+	return append([]string(nil), q.names...)
+}
+
+// Args returns a new set of query args with the named parameters filled in from values, in the order the names first
+// appear in the query (see Params), there must be exactly one value per name
+// Usage "args, err := q.Args(`open`, 42)" then "db.Query(q.SQL(), args...)"
+func (q *Query) Args(values ...interface{}) ([]interface{}, error) {
+	if len(values) != len(q.names) {
+		return nil, errors.New("query expects " + strconv.Itoa(len(q.names)) + " values, got " + strconv.Itoa(len(values)))
+	}
+
+	params := make(map[string]interface{}, len(values))
+	for i, name := range q.names {
+		params[name] = values[i]
+	}
+
+	return resolveNamed(q.args, params)
+}
+
+// Named is Args with the values given by name
+// Usage "args, err := q.Named(map[string]interface{}{"status": "open"})"
+func (q *Query) Named(params map[string]interface{}) ([]interface{}, error) {
+	return resolveNamed(q.args, params)
+}
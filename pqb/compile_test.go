@@ -0,0 +1,150 @@
+package pqb
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSqlbuilder_Compile(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	q, err := sqlb.From(`myschema.mytable`).
+		Where(`active`, `=`, `true`).
+		WhereNamed(`status`, `=`, `status`).
+		WhereRawArgs(`(owner_id = :user OR editor_id = :user)`).
+		Compile()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "status" = $2 AND (owner_id = $3 OR editor_id = $3)`
+	if q.SQL() != wantSql {
+		t.Errorf("got %v \nwanted %v", q.SQL(), wantSql)
+	}
+
+	if !reflect.DeepEqual(q.Params(), []string{`status`, `user`}) {
+		t.Errorf("param mismatch \ngot %v", q.Params())
+	}
+
+	gotArgs, err := q.Args(`open`, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantArgs := []interface{}{`true`, `open`, 7}
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	gotArgs, err = q.Named(map[string]interface{}{"status": "closed", "user": 8})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantArgs = []interface{}{`true`, `closed`, 8}
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	if _, err := q.Args(`open`); err == nil {
+		t.Errorf("expected an error for too few values")
+	}
+
+	// the compiled query is not changed by later use of the builder
+	sqlb.Where(`age`, `>`, `20`)
+	if q.SQL() != wantSql {
+		t.Errorf("got %v \nwanted %v", q.SQL(), wantSql)
+	}
+}
+
+func TestSqlbuilder_Compile_error(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	if _, err := sqlb.DeleteFrom(`myschema.mytable`).Compile(); err != ErrMissingWhere {
+		t.Errorf("got %v \nwanted %v", err, ErrMissingWhere)
+	}
+
+	if _, err := sqlb.Reset().Select(`id`).Compile(); err == nil {
+		t.Errorf("expected an error for a query without a table")
+	}
+}
+
+// The tests below are run with the race detector (go test -race) to check the compiled query and immutable builders can
+// be shared between goroutines
+
+func TestQuery_concurrent(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	q, err := sqlb.From(`myschema.mytable`).Where(`active`, `=`, `true`).WhereNamed(`id`, `=`, `id`).Compile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "id" = $2`
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			args, err := q.Args(i)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			args = append(args, `extra`)
+			args[0] = `changed`
+
+			if q.SQL() != wantSql {
+				t.Errorf("got %v \nwanted %v", q.SQL(), wantSql)
+			}
+
+			if again, _ := q.Args(i); !reflect.DeepEqual(again, []interface{}{`true`, i}) {
+				t.Errorf("argument mismatch \ngot %v \nwanted %v", again, []interface{}{`true`, i})
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestSqlbuilder_Immutable_concurrent(t *testing.T) {
+	base := (&Sqlbuilder{Immutable: true}).From(`myschema.mytable`).Where(`active`, `=`, `true`)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			id := strconv.Itoa(i)
+			gotSql, gotArgs := base.Where(`id`, `=`, id).OrderBy(`name`, `ASC`).Limit(10).Build()
+
+			wantSql := `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "id" = $2 ORDER BY "name" ASC LIMIT 10`
+			wantArgs := []interface{}{`true`, id}
+
+			if gotSql != wantSql {
+				t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+			}
+
+			if !reflect.DeepEqual(gotArgs, wantArgs) {
+				t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+			}
+
+			if countSql, _ := base.Count(); countSql == `` {
+				t.Errorf("count of the shared base failed")
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
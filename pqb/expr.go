@@ -0,0 +1,103 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"github.com/SamuelBanksTech/Go-Postgresql-Query-Builder/pqbExpr"
+)
+
+// exprBinder lets pqbExpr expressions bind their values into the builder's query args, filter marks a where or sort
+// expression so its columns are checked against AllowedColumns like the Where and OrderBy methods
+type exprBinder struct {
+	s      *Sqlbuilder
+	filter bool
+}
+
+func (b exprBinder) Bind(value interface{}) string {
+	return b.s.storeArg(value)
+}
+
+func (b exprBinder) Ident(identifier string) string {
+	if b.filter {
+		return b.s.formatColumn(identifier)
+	}
+
+	return b.s.formatSchema(identifier)
+}
+
+func (b exprBinder) Fail(err error) {
+	b.s.setErr(err)
+}
+
+// exprSql renders the expression, any values are bound in order at this point
+func (s *Sqlbuilder) exprSql(e pqbExpr.Expr) string {
+	return e.ToSql(exprBinder{s: s})
+}
+
+// filterExprSql is exprSql for a where or sort expression
+func (s *Sqlbuilder) filterExprSql(e pqbExpr.Expr) string {
+	return e.ToSql(exprBinder{s: s, filter: true})
+}
+
+// SelectExpr select an expression, alias can be left empty
+// Usage "xxx.From(`myschema.mytable`).SelectExpr(pqbExpr.Coalesce(pqbExpr.Col(`nickname`), pqbExpr.Col(`name`)), `display_name`)"
+func (s *Sqlbuilder) SelectExpr(e pqbExpr.Expr, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	s.selectStmt += s.withAlias(s.exprSql(e), alias) + `, `
+
+	return s
+}
+
+// WhereExpr adds an expression as a where clause, treated as AND WHERE like Where
+// Usage "xxx.From(`myschema.mytable`).WhereExpr(pqbExpr.Or(pqbExpr.Eq(pqbExpr.Col(`a`), pqbExpr.Val(1)), pqbExpr.IsNull(pqbExpr.Col(`b`))))"
+func (s *Sqlbuilder) WhereExpr(e pqbExpr.Expr) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.WhereRaw(s.filterExprSql(e))
+}
+
+// OrderByExpr order the returned rows by an expression in ASC or DESC order
+// Usage "xxx.From(`myschema.mytable`).OrderByExpr(pqbExpr.Func(`lower`, pqbExpr.Col(`name`)), `ASC`)"
+func (s *Sqlbuilder) OrderByExpr(e pqbExpr.Expr, direction string) *Sqlbuilder {
+	s = s.mutate()
+
+	bound := len(s.queryArgs)
+	s.orderbyStmt += s.filterExprSql(e) + ` ` + s.formatDirection(direction) + `, `
+	s.orderByBound(bound)
+
+	return s
+}
+
+// GroupByExpr group the returned rows by one or more expressions
+// Usage "xxx.From(`myschema.orders`).GroupByExpr(pqbExpr.Func(`date_trunc`, pqbExpr.Val(`day`), pqbExpr.Col(`created_at`)))"
+func (s *Sqlbuilder) GroupByExpr(exprs ...pqbExpr.Expr) *Sqlbuilder {
+	s = s.mutate()
+
+	for _, e := range exprs {
+		s.groupbyStmt += s.exprSql(e) + `, `
+	}
+
+	return s
+}
+
+// SetExpr sets a column to an expression in the next update
+// Usage "xxx.Where(`id`, `=`, `1`).SetExpr(`total`, pqbExpr.Mul(pqbExpr.Col(`price`), pqbExpr.Val(2))).BuildUpdate(`myschema.mytable`, nil)"
+func (s *Sqlbuilder) SetExpr(column string, e pqbExpr.Expr) *Sqlbuilder {
+	s = s.mutate()
+
+	s.setStmt += s.formatSchema(column) + ` = ` + s.exprSql(e) + `, `
+
+	return s
+}
@@ -0,0 +1,114 @@
+package pqb
+
+import (
+	"reflect"
+	"testing"
+
+	e "github.com/SamuelBanksTech/Go-Postgresql-Query-Builder/pqbExpr"
+)
+
+func TestSqlbuilder_Expr(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		Select(`id`).
+		SelectExpr(e.Coalesce(e.Col(`nickname`), e.Col(`name`), e.Val(`unknown`)), `display_name`).
+		SelectExpr(e.Case().When(e.Gt(e.Col(`score`), e.Val(50)), e.Val(`high`)).Else(e.Val(`low`)), `band`).
+		Where(`active`, `=`, `true`).
+		WhereExpr(e.Or(e.Eq(e.Func(`lower`, e.Col(`u.email`)), e.Val(`bob@example.com`)), e.Not(e.IsNull(e.Col(`deleted_at`))))).
+		WhereExpr(e.Lte(e.Add(e.Col(`a`), e.Mul(e.Col(`b`), e.Val(2))), e.Cast(e.Val(`10`), `int`))).
+		GroupByExpr(e.Func(`date_trunc`, e.Val(`day`), e.Col(`created_at`))).
+		OrderByExpr(e.Sub(e.Col(`score`), e.Val(1)), `desc`).
+		Build()
+
+	wantSql := `SELECT "id", COALESCE("nickname", "name", $1) AS "display_name", CASE WHEN ("score" > $2) THEN $3 ELSE $4 END AS "band" ` +
+		`FROM "myschema"."mytable" WHERE "active" = $5 AND ((lower("u"."email") = $6) OR NOT (("deleted_at" IS NULL))) AND (("a" + ("b" * $7)) <= CAST($8 AS int)) ` +
+		`GROUP BY date_trunc($9, "created_at") ORDER BY ("score" - $10) DESC`
+	wantArgs := []interface{}{`unknown`, 50, `high`, `low`, `true`, `bob@example.com`, 2, `10`, `day`, 1}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_SetExpr(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.Where(`id`, `=`, `1`).
+		SetExpr(`total`, e.Mul(e.Col(`price`), e.Val(2))).
+		SetExpr(`updated_at`, e.Raw(`NOW()`)).
+		BuildUpdate(`myschema.mytable`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "total" = ("price" * $2), "updated_at" = NOW() WHERE "id" = $1 `
+	wantArgs := []interface{}{`1`, 2}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_Expr_invalid_names(t *testing.T) {
+	tests := []struct {
+		name string
+		expr e.Expr
+	}{
+		{`function`, e.Func(`lower(email)) OR 1=1; --`, e.Col(`email`))},
+		{`cast`, e.Cast(e.Val(`1`), `int); DROP TABLE users; --`)},
+	}
+
+	for _, tt := range tests {
+		var sqlb Sqlbuilder
+
+		gotSql, _ := sqlb.From(`myschema.users`).SelectExpr(tt.expr, `x`).Build()
+
+		if gotSql != `` || sqlb.Err() == nil {
+			t.Errorf("%v: expected an error: got %v", tt.name, gotSql)
+		}
+	}
+
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.users`).
+		SelectExpr(e.Cast(e.Val(`1.5`), `numeric(10, 2)`), `a`).
+		SelectExpr(e.Cast(e.Func(`pg_catalog.now`), `timestamp with time zone`), `b`).
+		SelectExpr(e.Cast(e.Val(`{}`), `text[]`), `c`).
+		Build()
+
+	wantSql := `SELECT CAST($1 AS numeric(10, 2)) AS "a", CAST(pg_catalog.now() AS timestamp with time zone) AS "b", CAST($2 AS text[]) AS "c" FROM "myschema"."users"`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+}
+
+func TestSqlbuilder_Expr_empty(t *testing.T) {
+	tests := []struct {
+		name string
+		expr e.Expr
+	}{
+		{`and`, e.And()},
+		{`or`, e.Or()},
+		{`case`, e.Case().Else(e.Val(`x`))},
+	}
+
+	for _, tt := range tests {
+		var sqlb Sqlbuilder
+
+		gotSql, _ := sqlb.From(`myschema.users`).WhereExpr(tt.expr).Build()
+
+		if gotSql != `` || sqlb.Err() == nil {
+			t.Errorf("%v: expected an error: got %v", tt.name, gotSql)
+		}
+	}
+}
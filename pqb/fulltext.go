@@ -0,0 +1,130 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+// TsQueryMode is the postgres function used to turn the user supplied search text into a tsquery
+type TsQueryMode string
+
+const (
+	// PlainToTsQuery all words must match, punctuation is ignored
+	PlainToTsQuery TsQueryMode = "plainto_tsquery"
+	// WebSearchToTsQuery supports web search style syntax e.g. "quoted phrases", or, and -exclusions
+	WebSearchToTsQuery TsQueryMode = "websearch_to_tsquery"
+	// PhraseToTsQuery all words must match in the order given
+	PhraseToTsQuery TsQueryMode = "phraseto_tsquery"
+)
+
+// WhereFullText is a real full text search against a text column, the column is converted with to_tsvector and matched against the query
+// config is the text search configuration (e.g. `english`) and can be left empty to use the database default, mode defaults to PlainToTsQuery
+// The rank and headline helpers (and a config with WhereFullText) repeat the bound query so need the postgres dialect
+// Usage "xxx.From(`myschema.mytable`).WhereFullText(`body`, `revenge gopher`, `english`, pqb.WebSearchToTsQuery)"
+func (s *Sqlbuilder) WhereFullText(column string, query string, config string, mode TsQueryMode) *Sqlbuilder {
+	s = s.mutate()
+
+	if config != `` {
+		s.reusePlaceholder(`WhereFullText`)
+	}
+
+	cfg := s.storeTsConfig(config)
+
+	return s.whereFullText(`to_tsvector(`+cfg+s.formatColumn(column)+`)`, cfg, query, mode)
+}
+
+// WhereFullTextVector is the same as WhereFullText however the column is expected to already be a tsvector (usually an indexed generated column)
+// Usage "xxx.From(`myschema.mytable`).WhereFullTextVector(`search_vector`, `revenge gopher`, `english`, pqb.PlainToTsQuery)"
+func (s *Sqlbuilder) WhereFullTextVector(vectorColumn string, query string, config string, mode TsQueryMode) *Sqlbuilder {
+	s = s.mutate()
+
+	cfg := s.storeTsConfig(config)
+
+	return s.whereFullText(s.formatColumn(vectorColumn), cfg, query, mode)
+}
+
+// SelectRank adds the ts_rank of the last full text search to the select with the given alias
+// Usage "xxx.From(`myschema.mytable`).WhereFullText(`body`, `gopher`, `english`, pqb.PlainToTsQuery).SelectRank(`rank`)"
+func (s *Sqlbuilder) SelectRank(alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	if s.tsQuery == `` {
+		return s
+	}
+
+	s.reusePlaceholder(`SelectRank`)
+
+	s.selectStmt += s.tsRank() + ` AS ` + s.formatSchema(alias) + `, `
+	return s
+}
+
+// OrderByRank orders the returned rows by the ts_rank of the last full text search, usually DESC for best matches first
+// Usage "xxx.From(`myschema.mytable`).WhereFullText(`body`, `gopher`, `english`, pqb.PlainToTsQuery).OrderByRank(`DESC`)"
+func (s *Sqlbuilder) OrderByRank(direction string) *Sqlbuilder {
+	s = s.mutate()
+
+	if s.tsQuery == `` {
+		return s
+	}
+
+	s.reusePlaceholder(`OrderByRank`)
+
+	s.orderbyStmt += s.tsRank() + ` ` + s.formatDirection(direction) + `, `
+	return s
+}
+
+// SelectHeadline adds a ts_headline of the column highlighting the matches of the last full text search
+// options are the ts_headline options (e.g. `MaxWords=20, MinWords=5`) and can be left empty for the defaults
+// Usage "xxx.From(`myschema.mytable`).WhereFullText(`body`, `gopher`, `english`, pqb.PlainToTsQuery).SelectHeadline(`body`, `snippet`, `MaxWords=20`)"
+func (s *Sqlbuilder) SelectHeadline(column string, alias string, options string) *Sqlbuilder {
+	s = s.mutate()
+
+	if s.tsQuery == `` {
+		return s
+	}
+
+	s.reusePlaceholder(`SelectHeadline`)
+
+	headline := `ts_headline(` + s.tsConfig + s.formatSchema(column) + `, ` + s.tsQuery
+	if options != `` {
+		headline += `, ` + s.storeArg(options)
+	}
+	headline += `)`
+
+	s.selectStmt += headline + ` AS ` + s.formatSchema(alias) + `, `
+	return s
+}
+
+// storeTsConfig binds the text search config and returns it ready to be used as the first argument of a text search function
+func (s *Sqlbuilder) storeTsConfig(config string) string {
+	if config == `` {
+		return ``
+	}
+
+	return s.storeArg(config) + `::regconfig, `
+}
+
+// whereFullText adds the match condition and remembers the vector and query so they can be reused for ranking and headlines
+func (s *Sqlbuilder) whereFullText(vector string, cfg string, query string, mode TsQueryMode) *Sqlbuilder {
+	if mode == `` {
+		mode = PlainToTsQuery
+	}
+
+	s.tsVector = vector
+	s.tsConfig = cfg
+	s.tsQuery = string(mode) + `(` + cfg + s.storeArg(query) + `)`
+
+	return s.WhereRaw(s.tsVector + ` @@ ` + s.tsQuery)
+}
+
+func (s *Sqlbuilder) tsRank() string {
+	return `ts_rank(` + s.tsVector + `, ` + s.tsQuery + `)`
+}
@@ -0,0 +1,94 @@
+package pqb
+
+import (
+	"testing"
+)
+
+func TestSqlbuilder_WhereFullText(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereFullText(`mycol`, `revenge gopher`, `english`, WebSearchToTsQuery).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE to_tsvector($1::regconfig, "mycol") @@ websearch_to_tsquery($1::regconfig, $2)`
+	wantArgs := []interface{}{`english`, `revenge gopher`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereFullTextVector_Rank_Headline(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		Select(`id`).
+		WhereFullTextVector(`search_vector`, `gopher`, ``, ``).
+		SelectRank(`rank`).
+		SelectHeadline(`body`, `snippet`, `MaxWords=20`).
+		OrderByRank(`DESC`).
+		ThenOrderBy(`id`, `ASC`).
+		Build()
+
+	wantSql := `SELECT "id", ts_rank("search_vector", plainto_tsquery($1)) AS "rank", ts_headline("body", plainto_tsquery($1), $2) AS "snippet" FROM "myschema"."mytable" WHERE "search_vector" @@ plainto_tsquery($1) ORDER BY ts_rank("search_vector", plainto_tsquery($1)) DESC, "id" ASC`
+	wantArgs := []interface{}{`gopher`, `MaxWords=20`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_SelectRank_without_search(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).SelectRank(`rank`).OrderByRank(`DESC`).Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable"`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+}
+
+func TestSqlbuilder_FullText_unnumbered_dialect(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(s *Sqlbuilder) *Sqlbuilder
+	}{
+		{`config`, func(s *Sqlbuilder) *Sqlbuilder { return s.WhereFullText(`body`, `gopher`, `english`, PlainToTsQuery) }},
+		{`rank`, func(s *Sqlbuilder) *Sqlbuilder {
+			return s.WhereFullTextVector(`search`, `gopher`, ``, PlainToTsQuery).OrderByRank(`DESC`)
+		}},
+	}
+
+	for _, tt := range tests {
+		sqlb := Sqlbuilder{Dialect: `mysql`}
+
+		gotSql, _ := tt.build(sqlb.From(`mytable`)).Build()
+
+		if gotSql != `` || sqlb.Err() == nil {
+			t.Errorf("%v: expected an error: got %v", tt.name, gotSql)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// namedArg is stored in the query args in place of a named parameter until its value is given to BuildNamed
+type namedArg struct {
+	name string
+}
+
+// WhereNamed is Where with a named parameter in place of the value, the value is given later to BuildNamed
+// Usage "xxx.From(`myschema.mytable`).WhereNamed(`status`, `=`, `status`).BuildNamed(map[string]interface{}{"status": "active"})"
+func (s *Sqlbuilder) WhereNamed(column string, operator string, name string) *Sqlbuilder {
+	s = s.mutate()
+
+	s.whereStmt += s.formatColumn(column) + ` ` + strings.ToUpper(operator) + ` ` + s.storeNamed(name) + ` AND `
+
+	return s
+}
+
+// BuildNamed is Build with the values of any named parameters (e.g. :status in WhereRawArgs or WhereNamed) resolved into the
+// query args, a name used more than once is only bound once. An error is returned for a name without a value
+// Usage "sql, args, err := xxx.BuildNamed(map[string]interface{}{"status": "active"})"
+func (s *Sqlbuilder) BuildNamed(params map[string]interface{}) (string, []interface{}, error) {
+	sqlquery, args := s.build()
+	if sqlquery == `` {
+		if err := s.buildErr(); err != nil {
+			return ``, args, err
+		}
+		return ``, args, errors.New("sql build failed")
+	}
+
+	resolved, err := resolveNamed(args, params)
+	if err != nil {
+		return ``, args, err
+	}
+
+	return sqlquery, resolved, nil
+}
+
+// storeNamed adds a named parameter to the query args and returns its placeholder, with numbered placeholders a name that
+// has already been used shares the same placeholder
+func (s *Sqlbuilder) storeNamed(name string) string {
+	if s.numbered() {
+		for i, arg := range s.queryArgs {
+			if n, ok := arg.(namedArg); ok && n.name == name {
+				return `$` + strconv.Itoa(i+1)
+			}
+		}
+	}
+
+	return s.storeArg(namedArg{name})
+}
+
+// resolveNamed returns a copy of the args with every named parameter swapped for its value
+func resolveNamed(args []interface{}, params map[string]interface{}) ([]interface{}, error) {
+	resolved := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		n, ok := arg.(namedArg)
+		if !ok {
+			resolved[i] = arg
+			continue
+		}
+
+		value, ok := params[n.name]
+		if !ok {
+			return nil, errors.New("named parameter :" + n.name + " has no value")
+		}
+
+		resolved[i] = value
+	}
+
+	return resolved, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,91 @@
+package pqb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlbuilder_BuildNamed(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.From(`myschema.mytable`).
+		Where(`active`, `=`, `true`).
+		WhereNamed(`status`, `=`, `status`).
+		WhereRawArgs(`(owner_id = :user OR editor_id = :user) AND created_at::date > ?`, `2024-01-01`).
+		BuildNamed(map[string]interface{}{"status": "open", "user": 7})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "active" = $1 AND "status" = $2 AND (owner_id = $3 OR editor_id = $3) AND created_at::date > $4`
+	wantArgs := []interface{}{`true`, `open`, 7, `2024-01-01`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_BuildNamed_mysql(t *testing.T) {
+	sqlb := Sqlbuilder{Dialect: `mysql`}
+
+	gotSql, gotArgs, err := sqlb.From(`mytable`).
+		WhereRawArgs(`owner_id = :user OR editor_id = :user`).
+		BuildNamed(map[string]interface{}{"user": 7})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSql := "SELECT * FROM `mytable` WHERE owner_id = ? OR editor_id = ?"
+	wantArgs := []interface{}{7, 7}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_BuildNamed_missing(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _, err := sqlb.From(`myschema.mytable`).
+		WhereNamed(`status`, `=`, `status`).
+		BuildNamed(map[string]interface{}{"other": 1})
+
+	if err == nil || gotSql != `` {
+		t.Errorf("expected an error for a missing named parameter: got %v", gotSql)
+	}
+}
+
+func TestSqlbuilder_Build_unresolved_named(t *testing.T) {
+	var sqlb Sqlbuilder
+	sqlb.From(`myschema.mytable`).WhereNamed(`id`, `=`, `id`)
+
+	builds := map[string]func() (string, []interface{}){
+		"build":  sqlb.Build,
+		"count":  sqlb.Count,
+		"exists": sqlb.Exists,
+	}
+
+	for name, build := range builds {
+		if gotSql, _ := build(); gotSql != `` {
+			t.Errorf("%v: got %v \nwanted an empty query", name, gotSql)
+		}
+	}
+
+	if sqlb.Err() == nil {
+		t.Errorf("expected an error for an unresolved named parameter")
+	}
+
+	if _, _, err := sqlb.BuildNamed(map[string]interface{}{"id": 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// MatchMode controls where a LIKE pattern is allowed to match within the column value
+type MatchMode int
+
+const (
+	// MatchContains the value can appear anywhere in the column
+	MatchContains MatchMode = iota
+	// MatchPrefix the column must start with the value
+	MatchPrefix
+	// MatchSuffix the column must end with the value
+	MatchSuffix
+	// MatchExact the column must equal the value (case insensitive when used with WhereILike)
+	MatchExact
+)
+
+// likeEscaper escapes the LIKE wildcards so user input is always matched literally, backslash is also the postgres default
+// escape character so the escaping still holds where an ESCAPE clause is not allowed (e.g. ILIKE ANY)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// WhereLike case sensitive pattern match, any % or _ in the value are escaped and matched literally
+// Usage "xxx.From(`myschema.mytable`).WhereLike(`name`, `50%`, pqb.MatchPrefix)"
+func (s *Sqlbuilder) WhereLike(column string, value string, mode MatchMode) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereLike(column, `LIKE`, value, mode)
+}
+
+// WhereILike case insensitive pattern match, any % or _ in the value are escaped and matched literally
+// Usage "xxx.From(`myschema.mytable`).WhereILike(`name`, `bob`, pqb.MatchContains)"
+func (s *Sqlbuilder) WhereILike(column string, value string, mode MatchMode) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereLike(column, `ILIKE`, value, mode)
+}
+
+// WhereNotLike excludes rows that match the pattern, any % or _ in the value are escaped and matched literally
+// Usage "xxx.From(`myschema.mytable`).WhereNotLike(`name`, `test_`, pqb.MatchSuffix)"
+func (s *Sqlbuilder) WhereNotLike(column string, value string, mode MatchMode) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereLike(column, `NOT LIKE`, value, mode)
+}
+
+// WhereRegex POSIX regular expression match, operator can be "~" (case sensitive), "~*" (case insensitive), "!~" or "!~*" (not matching)
+// Any other operator is recorded as an error
+// Usage "xxx.From(`myschema.mytable`).WhereRegex(`email`, `~*`, `@example\.com$`)"
+func (s *Sqlbuilder) WhereRegex(column string, operator string, pattern string) *Sqlbuilder {
+	s = s.mutate()
+
+	switch operator {
+	case `~`, `~*`, `!~`, `!~*`:
+		return s.WhereRaw(s.formatColumn(column) + ` ` + operator + ` ` + s.storeArg(pattern))
+	default:
+		s.setErr(errors.New("regex: " + strconv.Quote(operator) + " is not allowed"))
+		return s
+	}
+}
+
+// WhereSimilarTo SQL standard regular expression match, the pattern is passed as is so can contain wildcards
+// Usage "xxx.From(`myschema.mytable`).WhereSimilarTo(`code`, `(AB|CD)[0-9]+`)"
+func (s *Sqlbuilder) WhereSimilarTo(column string, pattern string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.WhereRaw(s.formatColumn(column) + ` SIMILAR TO ` + s.storeArg(pattern))
+}
+
+func (s *Sqlbuilder) whereLike(column string, operator string, value string, mode MatchMode) *Sqlbuilder {
+	return s.WhereRaw(s.formatColumn(column) + ` ` + operator + ` ` + s.storeArg(likePattern(value, mode)) + ` ESCAPE ` + s.likeEscape())
+}
+
+// likeEscape is the ESCAPE string literal, backslash is an escape character in mysql string literals so has to be doubled
+func (s *Sqlbuilder) likeEscape() string {
+	if strings.ToLower(s.Dialect) == `mysql` {
+		return `'\\'`
+	}
+
+	return `'\'`
+}
+
+// likePattern escapes the value and wraps it in wildcards according to the match mode
+func likePattern(value string, mode MatchMode) string {
+	value = likeEscaper.Replace(value)
+
+	switch mode {
+	case MatchPrefix:
+		return value + `%`
+	case MatchSuffix:
+		return `%` + value
+	case MatchExact:
+		return value
+	default:
+		return `%` + value + `%`
+	}
+}
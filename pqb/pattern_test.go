@@ -0,0 +1,110 @@
+package pqb
+
+import (
+	"testing"
+)
+
+func TestSqlbuilder_WhereLike(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereLike(`mycol`, `50%`, MatchPrefix).
+		WhereILike(`mycol2`, `my_name`, MatchContains).
+		WhereNotLike(`mycol3`, `a\b`, MatchSuffix).
+		WhereILike(`mycol4`, `exact`, MatchExact).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "mycol" LIKE $1 ESCAPE '\' AND "mycol2" ILIKE $2 ESCAPE '\' AND "mycol3" NOT LIKE $3 ESCAPE '\' AND "mycol4" ILIKE $4 ESCAPE '\'`
+	wantArgs := []interface{}{`50\%%`, `%my\_name%`, `%a\\b`, `exact`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereRegex_SimilarTo(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereRegex(`email`, `~*`, `@example\.com$`).
+		WhereSimilarTo(`code`, `(AB|CD)[0-9]+`).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "email" ~* $1 AND "code" SIMILAR TO $2`
+	wantArgs := []interface{}{`@example\.com$`, `(AB|CD)[0-9]+`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereStringMatchAny_escaping(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	_, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereStringMatchAny(`mycol`, []string{`50%`, `a_b`}).
+		Build()
+
+	wantArgs := []interface{}{`%50\%%`, `%a\_b%`}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereRegex_invalid_operator(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		WhereRegex(`email`, `; DROP`, `ignored`).
+		Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for an invalid regex operator: got %v", gotSql)
+	}
+}
+
+func TestSqlbuilder_WhereLike_mysql(t *testing.T) {
+	sqlb := Sqlbuilder{Dialect: `mysql`}
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereLike(`mycol`, `50%`, MatchPrefix).
+		Build()
+
+	wantSql := "SELECT * FROM `myschema`.`mytable` WHERE `mycol` LIKE ? ESCAPE '\\\\'"
+	wantArgs := []interface{}{`50\%%`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) || gotArgs[0] != wantArgs[0] {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
@@ -20,28 +20,48 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
+// ErrMissingWhere is returned when an UPDATE or DELETE has no WHERE clause and AllowFullTable has not been called
+var ErrMissingWhere = errors.New("refusing to update or delete every row without a where clause, use AllowFullTable to override")
+
 // Sqlbuilder instanciate this struct and add query parts using attached methods, finally call Build, or use BuildInsert, BuildUpdate, or DeleteFrom
 type Sqlbuilder struct {
-	string         string
-	selectStmt     string
-	whereStmt      string
-	whereinStmt    string
-	fromStmt       string
-	deletefromStmt string
-	leftjoinStmt   string
-	limitStmt      string
-	offsetStmt     string
-	orderbyStmt    string
-	Dialect        string //Can be postgres or mysql atm (more to come)
-	Distinct       bool
-	queryArgs      []interface{}
+	selectStmt        string
+	whereStmt         string
+	whereinStmt       string
+	fromStmt          string
+	deletefromStmt    string
+	leftjoinStmt      string
+	limitStmt         string
+	offsetStmt        string
+	orderbyStmt       string
+	orderbyArgs       bool
+	groupbyStmt       string
+	setStmt           string
+	allowFullTable    bool
+	err               error
+	tsVector          string
+	tsQuery           string
+	tsConfig          string
+	geoColumn         string
+	geoPoint          string
+	Dialect           string //Can be postgres or mysql atm (more to come)
+	Distinct          bool
+	Spatial           GeoCast                   //Can be geography (default) or geometry, used by the PostGIS helpers
+	Naming            pqbHelpers.NamingStrategy //Column names for struct fields without a pqb tag, defaults to snake case
+	StrictIdentifiers bool                      //Only allow identifiers matching [A-Za-z_][A-Za-z0-9_$]*, recommended when identifiers come from user input
+	AllowedColumns    ColumnSet                 //When set only these columns can be used to filter (Where...) and sort (OrderBy...)
+	Immutable         bool                      //Every method returns a changed copy and leaves the builder it was called on untouched
+	queryArgs         []interface{}
 }
 
 // From portion of query:
 // Usage "xxx.From(`myschema.mytable`)"
 func (s *Sqlbuilder) From(schemaTable string) *Sqlbuilder {
+	s = s.mutate()
+
 	s.fromStmt = s.formatSchema(schemaTable)
 
 	return s
@@ -50,6 +70,8 @@ func (s *Sqlbuilder) From(schemaTable string) *Sqlbuilder {
 // SelectRaw query, for use when doing advanced selects (usually CASE WHEN etc) without any helper intervention
 // Usage "xxx.From(`myschema.mytable`).SelectRaw(`CASE blah blah blah`)"
 func (s *Sqlbuilder) SelectRaw(selectStmt string) *Sqlbuilder {
+	s = s.mutate()
+
 	re := regexp.MustCompile(`\r?\n`)
 	selectStmt = re.ReplaceAllString(selectStmt, " ")
 
@@ -58,19 +80,25 @@ func (s *Sqlbuilder) SelectRaw(selectStmt string) *Sqlbuilder {
 }
 
 // Select statement, select the table columns you want returned, no more explanation required surely
+// Columns can be aliased with AS and simple aggregates (COUNT, SUM, AVG, MIN, MAX) are recognised
 // Usage "xxx.From(`myschema.mytable`).Select(`id`, `name`, `telephone`)"
+// Usage 2 "xxx.From(`myschema.mytable`).Select(`u.name AS author`, `count(*) AS total`)"
 func (s *Sqlbuilder) Select(selectStmt ...string) *Sqlbuilder {
+	s = s.mutate()
 
 	for _, ss := range selectStmt {
-		s.selectStmt += s.formatSchema(ss) + `, `
+		s.selectStmt += s.formatSelect(ss) + `, `
 	}
 
 	return s
 }
 
 // DeleteFrom If deleteing from a table use this instead of the above From command
+// A Where is required unless AllowFullTable is used, otherwise Build returns an empty query and Err returns ErrMissingWhere
 // Usage "xxx.DeleteFrom(`myschema.mytable`)"
 func (s *Sqlbuilder) DeleteFrom(schemaTable string) *Sqlbuilder {
+	s = s.mutate()
+
 	s.deletefromStmt = s.formatSchema(schemaTable)
 
 	return s
@@ -79,12 +107,16 @@ func (s *Sqlbuilder) DeleteFrom(schemaTable string) *Sqlbuilder {
 // storeVal is a private function to add the values to an string slice that will be returned as the second param from build
 // this is to be passed as the second param in db connection this ensure security of prepared statements
 func (s *Sqlbuilder) storeVal(value string) string {
+	return s.storeArg(pqbHelpers.SanitiseString(value))
+}
+
+// storeArg adds a value to the query args untouched (no string conversion or sanitising) and returns its placeholder
+func (s *Sqlbuilder) storeArg(value interface{}) string {
 	var returnPS string
 
-	s.queryArgs = append(s.queryArgs, pqbHelpers.SanitiseString(value))
-	s.Dialect = strings.ToLower(s.Dialect)
+	s.queryArgs = append(s.queryArgs, value)
 
-	switch s.Dialect {
+	switch strings.ToLower(s.Dialect) {
 	case "":
 		returnPS = "$" + strconv.Itoa(len(s.queryArgs))
 		break
@@ -98,11 +130,27 @@ func (s *Sqlbuilder) storeVal(value string) string {
 	return returnPS
 }
 
+// numbered reports if the dialect uses numbered ($n) placeholders, only then can a placeholder be used more than once
+func (s *Sqlbuilder) numbered() bool {
+	dialect := strings.ToLower(s.Dialect)
+
+	return dialect == `` || dialect == `postgres`
+}
+
+// reusePlaceholder records an error for Err when a query part that repeats a placeholder is used with a dialect that
+// has ? placeholders, as each ? needs its own arg
+func (s *Sqlbuilder) reusePlaceholder(method string) {
+	if !s.numbered() {
+		s.setErr(errors.New(method + ": repeats a bound value which needs numbered placeholders, not supported by the " + s.Dialect + " dialect"))
+	}
+}
+
 // Where statement, accepts 3 arguments a column, and operator (can be "=", "!=", ">(=)", "<(=)", "BETWEEN" or any other valid postgres comparison operator)
 // You can add as many .Where clauses as you wish they will be treated as AND WHERE
 // Usage "xxx.From(`myschema.mytable`).Where(`name`, `=`, `superman`)"
 // Usage 2 "xxx.From(`myschema.mytable`).Where(`age`, `BETWEEN`, `20 AND 30`)"
 func (s *Sqlbuilder) Where(column string, operator string, value string) *Sqlbuilder {
+	s = s.mutate()
 
 	operator = strings.ToUpper(operator)
 	value = strings.TrimSuffix(value, `'`)
@@ -130,7 +178,7 @@ func (s *Sqlbuilder) Where(column string, operator string, value string) *Sqlbui
 		value = s.storeVal(value)
 	}
 
-	s.whereStmt += s.formatSchema(column) + " " + operator + " " + value + ` AND `
+	s.whereStmt += s.formatColumn(column) + " " + operator + " " + value + ` AND `
 
 	return s
 }
@@ -138,6 +186,7 @@ func (s *Sqlbuilder) Where(column string, operator string, value string) *Sqlbui
 // OrWhere dependant on where it is called it will supersede all other where clauses that have been added before it
 // Usage "xxx.From(`myschema.mytable`).Where(`name`, `=`, `superman`).OrWhere(`name`, `=`, `spiderman`)"
 func (s *Sqlbuilder) OrWhere(column string, operator string, value string) *Sqlbuilder {
+	s = s.mutate()
 
 	operator = strings.ToUpper(operator)
 	value = strings.TrimSuffix(value, `'`)
@@ -166,7 +215,7 @@ func (s *Sqlbuilder) OrWhere(column string, operator string, value string) *Sqlb
 	}
 
 	s.whereStmt = strings.TrimSuffix(s.whereStmt, ` AND `)
-	s.whereStmt += ` OR ` + s.formatSchema(column) + " " + operator + " " + value + ` AND `
+	s.whereStmt += ` OR ` + s.formatColumn(column) + " " + operator + " " + value + ` AND `
 
 	return s
 }
@@ -175,6 +224,8 @@ func (s *Sqlbuilder) OrWhere(column string, operator string, value string) *Sqlb
 // WARNING do not use for user input this could pose a security risk
 // Usage "xxx.From(`myschema.mytable`).WhereRaw(`WHERE SOME COMPLEX QUERY`)"
 func (s *Sqlbuilder) WhereRaw(whereStmt string) *Sqlbuilder {
+	s = s.mutate()
+
 	s.whereStmt += whereStmt + ` AND `
 	return s
 }
@@ -182,6 +233,7 @@ func (s *Sqlbuilder) WhereRaw(whereStmt string) *Sqlbuilder {
 // WhereIn Accepts Slice of INT, FLOAT32, FLOAT64, STRING
 // Usage "xxx.From(`myschema.mytable`).WhereIn(`age`, []int{20, 25, 30 ,35})"
 func (s *Sqlbuilder) WhereIn(column string, params interface{}) *Sqlbuilder {
+	s = s.mutate()
 
 	output := ""
 
@@ -223,49 +275,51 @@ func (s *Sqlbuilder) WhereIn(column string, params interface{}) *Sqlbuilder {
 	}
 
 	if output != "" {
-		s.WhereRaw(s.formatSchema(column) + ` IN ` + output)
+		s = s.WhereRaw(s.formatColumn(column) + ` IN ` + output)
 	}
 
 	return s
 }
 
 // WhereStringMatchAny is used for psudo full text search, this function can (case insensitivly) find a string within a string in postgres
-// It will return any rows that have at least one of the string in the slice
+// It will return any rows that have at least one of the string in the slice, any % or _ in the strings are matched literally
 // Usage "xxx.From(`myschema.mytable`).WhereStringMatchAny(`name`, []string{"bob", "BILLY"})
 func (s *Sqlbuilder) WhereStringMatchAny(column string, params []string) *Sqlbuilder {
+	s = s.mutate()
 
 	output := ""
 
 	output += "(array["
 	for _, v := range params {
-		output += s.storeVal(`%`+pqbHelpers.SanitiseString(strings.TrimSpace(v))+`%`) + `, `
+		output += s.storeArg(likePattern(strings.TrimSpace(v), MatchContains)) + `, `
 	}
 	output = strings.TrimSuffix(output, ", ")
 	output += "])"
 
 	if output != "" {
-		s.WhereRaw(s.formatSchema(column) + ` ILIKE ANY ` + output)
+		s = s.WhereRaw(s.formatColumn(column) + ` ILIKE ANY ` + output)
 	}
 
 	return s
 }
 
 // WhereStringMatchAll is used for psudo full text search, this function can (case insensitivly) find a string within a string in postgres
-// It will only return rows that have ALL of the strings in the slice
+// It will only return rows that have ALL of the strings in the slice, any % or _ in the strings are matched literally
 // Usage "xxx.From(`myschema.mytable`).WhereStringMatchAny(`name`, []string{"bob", "BILLY"})
 func (s *Sqlbuilder) WhereStringMatchAll(column string, params []string) *Sqlbuilder {
+	s = s.mutate()
 
 	output := ""
 
 	output += "(array["
 	for _, v := range params {
-		output += s.storeVal(`%`+pqbHelpers.SanitiseString(strings.TrimSpace(v))+`%`) + `, `
+		output += s.storeArg(likePattern(strings.TrimSpace(v), MatchContains)) + `, `
 	}
 	output = strings.TrimSuffix(output, ", ")
 	output += "])"
 
 	if output != "" {
-		s.WhereRaw(s.formatSchema(column) + ` ILIKE ALL ` + output)
+		s = s.WhereRaw(s.formatColumn(column) + ` ILIKE ALL ` + output)
 	}
 
 	return s
@@ -274,6 +328,7 @@ func (s *Sqlbuilder) WhereStringMatchAll(column string, params []string) *Sqlbui
 // LeftJoin for joining another table linked by a condition
 // Usage "xxx.From(`myschema.mytable`).LeftJoin(`myschema.myothertable`, `mot`, `myschema.mytable.mot_id = mot.id`)
 func (s *Sqlbuilder) LeftJoin(table string, as string, on string) *Sqlbuilder {
+	s = s.mutate()
 
 	table = s.formatSchema(table)
 	on = s.formatJoinOn(on)
@@ -286,17 +341,20 @@ func (s *Sqlbuilder) LeftJoin(table string, as string, on string) *Sqlbuilder {
 // LeftJoinExtended for joining another table linked by a condition with advance additional commands
 // Usage "xxx.From(`myschema.mytable`).LeftJoinExtended(`myschema.myothertable`, `mot`, `myschema.mytable.mot_id = mot.id`, `AND mod.limitingvalue BETWEEN 10 AND 50`)
 func (s *Sqlbuilder) LeftJoinExtended(table string, as string, on string, additionalQuery string) *Sqlbuilder {
+	s = s.mutate()
 
 	table = s.formatSchema(table)
 	on = s.formatJoinOn(on)
 
-	s.leftjoinStmt += `LEFT JOIN ` + table + ` AS "` + as + `" ON ` + on + ` ` + additionalQuery + ` `
+	s.leftjoinStmt += `LEFT JOIN ` + table + ` AS ` + s.formatSchema(as) + ` ON ` + on + ` ` + additionalQuery + ` `
 	return s
 }
 
 // Limit the amount of rows returned
 // Usage "xxx.From(`myschema.mytable`).Select(`id`, `name`).Limit(10)
 func (s *Sqlbuilder) Limit(limit int) *Sqlbuilder {
+	s = s.mutate()
+
 	s.limitStmt = `LIMIT ` + strconv.Itoa(limit) + ` `
 
 	return s
@@ -305,22 +363,51 @@ func (s *Sqlbuilder) Limit(limit int) *Sqlbuilder {
 // Offset the selection of rows used in conjustion with limit
 // Usage "xxx.From(`myschema.mytable`).Select(`id`, `name`).Limit(10).Offset(20)
 func (s *Sqlbuilder) Offset(offset int) *Sqlbuilder {
+	s = s.mutate()
+
 	s.offsetStmt = `OFFSET ` + strconv.Itoa(offset) + ` `
 
 	return s
 }
 
-// OrderBy order the returned rows by a column in ASC (ascending) or DESC (descending) order
+// OrderBy order the returned rows by a column in ASC (ascending) or DESC (descending) order, optionally with NULLS FIRST or NULLS LAST
+// OrderBy replaces any ordering added before it, use ThenOrderBy to sort by more columns
+// An ordering with bound values (e.g. OrderBySimilarity) can not be replaced as its args are already in the query, call
+// OrderBy first and add to it with ThenOrderBy, otherwise the error is recorded for Err and the query will not build
 // Usage "xxx.From(`myschema.mytable`).Select(`id`, `name`).OrderBy(`id`, `DESC`)
 func (s *Sqlbuilder) OrderBy(column string, diretion string) *Sqlbuilder {
-	s.orderbyStmt = `ORDER BY "` + column + `" ` + diretion
+	s = s.mutate()
+
+	if s.orderbyArgs {
+		s.setErr(errors.New("OrderBy: would replace an ordering with bound values, use ThenOrderBy to add to it"))
+	}
+
+	s.orderbyStmt = s.formatColumn(column) + ` ` + s.formatDirection(diretion) + `, `
+
+	return s
+}
+
+// orderByBound notes that the ordering just added bound values, bound is the number of args before it was added
+func (s *Sqlbuilder) orderByBound(bound int) {
+	if len(s.queryArgs) > bound {
+		s.orderbyArgs = true
+	}
+}
+
+// ThenOrderBy adds a column to the ordering after any added before it (OrderBy, OrderByRank, OrderByDistance etc)
+// Usage "xxx.From(`myschema.mytable`).OrderBy(`surname`, `ASC`).ThenOrderBy(`id`, `DESC`)
+func (s *Sqlbuilder) ThenOrderBy(column string, direction string) *Sqlbuilder {
+	s = s.mutate()
+
+	s.orderbyStmt += s.formatColumn(column) + ` ` + s.formatDirection(direction) + `, `
 
 	return s
 }
 
 // Reset clears any previously defined query parts, allows the reuse of an instance
 func (s *Sqlbuilder) Reset() *Sqlbuilder {
-	s.string = ``
+	s = s.mutate()
+
 	s.selectStmt = ``
 	s.whereStmt = ``
 	s.whereinStmt = ``
@@ -330,15 +417,75 @@ func (s *Sqlbuilder) Reset() *Sqlbuilder {
 	s.limitStmt = ``
 	s.offsetStmt = ``
 	s.orderbyStmt = ``
+	s.orderbyArgs = false
+	s.groupbyStmt = ``
+	s.setStmt = ``
+	s.allowFullTable = false
+	s.err = nil
+	s.tsVector = ``
+	s.tsQuery = ``
+	s.tsConfig = ``
+	s.geoColumn = ``
+	s.geoPoint = ``
 	s.queryArgs = nil
 
 	return s
 }
 
+// AllowFullTable opts in to an UPDATE or DELETE without any WHERE clause, otherwise they are refused to avoid accidental data loss
+// Like the other query parts it is cleared by Reset, so it only applies to the query being built
+// Usage "xxx.DeleteFrom(`myschema.mytable`).AllowFullTable().Build()"
+func (s *Sqlbuilder) AllowFullTable() *Sqlbuilder {
+	s = s.mutate()
+
+	s.allowFullTable = true
+
+	return s
+}
+
+// Err returns the reason Build returned an empty query, or nil if the query can be built
+// A query with named parameters (see WhereNamed) can only be built with BuildNamed or Compile
+func (s *Sqlbuilder) Err() error {
+	if err := s.buildErr(); err != nil {
+		return err
+	}
+
+	for _, arg := range s.queryArgs {
+		if n, ok := arg.(namedArg); ok {
+			return errors.New("named parameter :" + n.name + " has no value, use BuildNamed or Compile")
+		}
+	}
+
+	return nil
+}
+
+// buildErr is Err without the check for named parameters, for the builds that resolve them
+func (s *Sqlbuilder) buildErr() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.deletefromStmt != `` && s.fromStmt == `` && s.whereStmt == `` && !s.allowFullTable {
+		return ErrMissingWhere
+	}
+
+	return nil
+}
+
+// setErr records the first error found while adding query parts
+func (s *Sqlbuilder) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
 // Count allows the result of a query to be returned as a numeric amount rather than the actual rows
 // You can call count instead of build or you can call count then conditionally call build afterwards
 func (s *Sqlbuilder) Count() (string, []interface{}) {
 	sqlquery, args := s.Build()
+	if sqlquery == `` {
+		return ``, args
+	}
 
 	countQuery := `SELECT COUNT(*) AS rowcount FROM (` + sqlquery + `) AS rowdata`
 
@@ -346,6 +493,9 @@ func (s *Sqlbuilder) Count() (string, []interface{}) {
 }
 func (s *Sqlbuilder) Exists() (string, []interface{}) {
 	sqlquery, args := s.Build()
+	if sqlquery == `` {
+		return ``, args
+	}
 
 	existsQuery := `SELECT EXISTS (` + sqlquery + `)`
 
@@ -354,8 +504,25 @@ func (s *Sqlbuilder) Exists() (string, []interface{}) {
 
 // Build is the main function of the query builder, it is the final function that takes all the query parts and puts them together
 // in a sanitised query ready for passing to a database connection
+// An empty query is returned if it can not be built, e.g. a DeleteFrom without a Where, Err returns the reason
 func (s *Sqlbuilder) Build() (string, []interface{}) {
 
+	if s.Err() != nil {
+		return ``, s.queryArgs
+	}
+
+	return s.build()
+}
+
+// build puts the query together leaving any named parameters in the args
+func (s *Sqlbuilder) build() (string, []interface{}) {
+
+	if s.buildErr() != nil {
+		return ``, s.queryArgs
+	}
+
+	var query string
+
 	//build selects
 	if s.deletefromStmt == `` {
 
@@ -365,93 +532,205 @@ func (s *Sqlbuilder) Build() (string, []interface{}) {
 		}
 
 		if s.selectStmt == `` {
-			s.string = `SELECT` + dis + ` * `
+			query = `SELECT` + dis + ` * `
 		} else {
-			s.string = `SELECT` + dis + ` ` + strings.TrimSuffix(s.selectStmt, `, `) + ` `
+			query = `SELECT` + dis + ` ` + strings.TrimSuffix(s.selectStmt, `, `) + ` `
 		}
 	}
 
 	//build from
 	if s.fromStmt == `` {
 		if s.deletefromStmt != `` {
-			s.string += `DELETE FROM ` + strings.TrimSuffix(s.deletefromStmt, `.`) + ` `
+			query += `DELETE FROM ` + strings.TrimSuffix(s.deletefromStmt, `.`) + ` `
 		} else {
 			return ``, s.queryArgs
 		}
 	} else {
-		s.string += `FROM ` + strings.TrimSuffix(s.fromStmt, `.`) + ` `
+		query += `FROM ` + strings.TrimSuffix(s.fromStmt, `.`) + ` `
 	}
 
 	//left joins
-	s.string += s.leftjoinStmt + ` `
+	query += s.leftjoinStmt + ` `
 
 	//where
 	if s.whereStmt != `` {
-		s.string += `WHERE ` + strings.TrimSuffix(s.whereStmt, ` AND `) + ` `
+		query += `WHERE ` + strings.TrimSuffix(s.whereStmt, ` AND `) + ` `
+	}
+
+	//groupby
+	if s.groupbyStmt != `` {
+		query += `GROUP BY ` + strings.TrimSuffix(s.groupbyStmt, `, `) + ` `
 	}
 
 	//orderby
 	if s.orderbyStmt != `` {
-		s.string += s.orderbyStmt + ` `
+		query += `ORDER BY ` + strings.TrimSuffix(s.orderbyStmt, `, `) + ` `
 	}
 
 	//limit and offset
-	s.string += s.limitStmt
-	s.string += s.offsetStmt
+	query += s.limitStmt
+	query += s.offsetStmt
 
 	space := regexp.MustCompile(`\s+`)
-	s.string = space.ReplaceAllString(s.string, " ")
-
-	returnString := s.string
+	query = space.ReplaceAllString(query, " ")
 
-	return strings.TrimSpace(returnString), s.queryArgs
+	return strings.TrimSpace(query), append([]interface{}(nil), s.queryArgs...)
 }
 
 // BuildInsert is a very simple yet powerful feature that saves a lot of time, you simply pass a schema and table ref and a struct of data
 // the builder will automatically build the insert based on the struct value names and values if the field tag of "pqb" is used one can
-// override the struct name
+// override the struct name. Fields tagged "readonly" are never inserted, "pk" and "omitempty" fields are left out when zero so the
+// database default (e.g. a serial id) is used
+// data can be a struct, a pointer to a struct or a slice of either, a slice is inserted as a single multi row insert where
+// left out values are written as DEFAULT, a single row with nothing to write is inserted with DEFAULT VALUES
 func (s *Sqlbuilder) BuildInsert(table string, data interface{}, additionalQuery string) (string, []interface{}, error) {
 
+	s = s.mutate()
 	defer s.Reset()
 
-	dbCols, dbVals, err := pqbHelpers.MapStruct(data)
+	rows, err := s.mapper().MapRows(data)
 	if err != nil {
 		return "", s.queryArgs, err
 	}
 
-	insertString := ``
+	if len(rows) == 0 {
+		return "", s.queryArgs, errors.New("data: no rows to insert")
+	}
+
+	var dbCols []string
+	var valueStrings []string
+
+	if len(rows) == 1 {
+		insertString := ``
+
+		for _, f := range rows[0] {
+			if f.ReadOnly || ((f.PrimaryKey || f.OmitEmpty) && f.Zero) {
+				continue
+			}
+
+			dbCols = append(dbCols, f.Column)
+			insertString += s.storeArg(f.Value) + `, `
+		}
+
+		valueStrings = append(valueStrings, `(`+strings.TrimSuffix(insertString, `, `)+`)`)
+	} else {
+		dbCols = insertColumns(rows)
+
+		for _, row := range rows {
+			rowFields := make(map[string]pqbHelpers.Field, len(row))
+			for _, f := range row {
+				rowFields[f.Column] = f
+			}
+
+			insertString := ``
+
+			for _, col := range dbCols {
+				f, ok := rowFields[col]
+				if !ok || ((f.PrimaryKey || f.OmitEmpty) && f.Zero) {
+					insertString += `DEFAULT, `
+				} else {
+					insertString += s.storeArg(f.Value) + `, `
+				}
+			}
+
+			valueStrings = append(valueStrings, `(`+strings.TrimSuffix(insertString, `, `)+`)`)
+		}
+	}
+
+	tableName := s.formatSchema(table)
+	if s.err != nil {
+		return "", s.queryArgs, s.err
+	}
+
+	if len(dbCols) == 0 {
+		// nothing left to write, a single row is inserted with every column set to its default
+		if len(rows) > 1 {
+			return "", s.queryArgs, errors.New("data: no columns to insert")
+		}
 
-	for _, val := range dbVals {
-		insertString += s.storeVal(val) + `, `
+		return "INSERT INTO " + tableName + " DEFAULT VALUES " + additionalQuery, s.queryArgs, nil
 	}
-	insertString = strings.TrimSuffix(insertString, `, `)
 
-	sql := "INSERT INTO " + s.formatSchema(table) + " (" + strings.Join(dbCols, ", ") + ") VALUES (" + insertString + ") " + additionalQuery
+	sql := "INSERT INTO " + tableName + " (" + strings.Join(dbCols, ", ") + ") VALUES " + strings.Join(valueStrings, ", ") + " " + additionalQuery
 
 	return sql, s.queryArgs, nil
 }
 
+// insertColumns returns every writable column found across the rows in the order they first appear
+func insertColumns(rows [][]pqbHelpers.Field) []string {
+	var dbCols []string
+	seen := make(map[string]bool)
+
+	for _, row := range rows {
+		for _, f := range row {
+			if f.ReadOnly || seen[f.Column] {
+				continue
+			}
+
+			seen[f.Column] = true
+			dbCols = append(dbCols, f.Column)
+		}
+	}
+
+	return dbCols
+}
+
 // BuildUpdate like the buildinsert takes a table and a struct of data, however unlike buildinsert buildupdate will look to replace all
-// matching column names always best to ensure to use a Where query part to avoid accidental data loss
+// matching column names, without a Where query part (or "pk" field) ErrMissingWhere is returned unless AllowFullTable is used
+// Fields tagged "pk" are not SET but are added to the WHERE, "readonly" fields are never updated and "omitempty" fields are left out when zero
+// data can be a struct or a pointer to a struct, or nil when only Set, SetRaw or Increment are used
 func (s *Sqlbuilder) BuildUpdate(table string, data interface{}) (string, []interface{}, error) {
 
+	s = s.mutate()
 	defer s.Reset()
 
-	dbCols, dbVals, err := pqbHelpers.MapStruct(data)
-	if err != nil {
-		return "", s.queryArgs, err
+	var fields []pqbHelpers.Field
+
+	if data != nil {
+		var err error
+		fields, err = s.mapper().MapFields(data)
+		if err != nil {
+			return "", s.queryArgs, err
+		}
 	}
 
+	return s.buildUpdate(table, fields)
+}
+
+// buildUpdate puts together the update from the mapped fields followed by anything added with Set, SetRaw or Increment
+func (s *Sqlbuilder) buildUpdate(table string, fields []pqbHelpers.Field) (string, []interface{}, error) {
 	setString := ""
 	sql := ""
 
-	for i, col := range dbCols {
-		setString += col + ` = ` + s.storeVal(dbVals[i]) + `, `
+	var primaryKeys []pqbHelpers.Field
+
+	for _, f := range fields {
+		switch {
+		case f.PrimaryKey:
+			primaryKeys = append(primaryKeys, f)
+		case f.ReadOnly, f.OmitEmpty && f.Zero:
+		default:
+			setString += f.Column + ` = ` + s.storeArg(f.Value) + `, `
+		}
+	}
+
+	setString += s.setStmt
+
+	for _, pk := range primaryKeys {
+		s = s.WhereRaw(pk.Column + ` = ` + s.storeArg(pk.Value))
+	}
+
+	tableName := s.formatSchema(table)
+	if s.err != nil {
+		return "", s.queryArgs, s.err
 	}
-	setString = strings.TrimSuffix(setString, `, `) + ` `
 
 	if setString != "" {
-		sql = "UPDATE " + s.formatSchema(table) + ` SET ` + setString
+		if s.whereStmt == `` && !s.allowFullTable {
+			return "", s.queryArgs, ErrMissingWhere
+		}
+
+		sql = "UPDATE " + tableName + ` SET ` + strings.TrimSuffix(setString, `, `) + ` `
 
 		if s.whereStmt != `` {
 			sql += `WHERE ` + strings.TrimSuffix(s.whereStmt, ` AND `) + ` `
@@ -463,12 +742,17 @@ func (s *Sqlbuilder) BuildUpdate(table string, data interface{}) (string, []inte
 	return sql, s.queryArgs, errors.New("sql build failed")
 }
 
+// mapper returns the struct mapper for the builder's naming strategy
+func (s *Sqlbuilder) mapper() pqbHelpers.Mapper {
+	return pqbHelpers.Mapper{Naming: s.Naming}
+}
+
 // Based upon dialect this function will split a string schema-table reference into the correct
 // format required. e.g. `myschema.mytable` into "myschema"."mytable"
+// Parts already in quotes are kept as they are (so can contain dots), any quote characters inside a part are escaped by doubling
+// An empty part, a control character or (with StrictIdentifiers) anything other than [A-Za-z_][A-Za-z0-9_$]* is refused, the
+// error is recorded for Err and the query will not build
 func (s *Sqlbuilder) formatSchema(schema string) string {
-	schemaParts := strings.Split(schema, ".")
-	finalSchemaStmt := ``
-
 	var dialectFormat string
 
 	switch strings.ToLower(s.Dialect) {
@@ -482,23 +766,113 @@ func (s *Sqlbuilder) formatSchema(schema string) string {
 		dialectFormat = `"`
 	}
 
-	for _, v := range schemaParts {
-		if v == `*` {
-			finalSchemaStmt += `*`
-		} else {
-			part := strings.TrimSpace(v)
-			if string(part[0]) == dialectFormat && string(part[len(part)-1]) == dialectFormat {
-				finalSchemaStmt += part + `.`
-			} else {
-				finalSchemaStmt += dialectFormat + part + dialectFormat + `.`
-			}
+	schemaParts, err := splitIdentifier(schema, dialectFormat[0])
+	if err != nil {
+		s.setErr(err)
+		return dialectFormat + dialectFormat
+	}
 
+	finalSchemaStmt := ``
+
+	for _, part := range schemaParts {
+		if part == `*` {
+			finalSchemaStmt += `*.`
+			continue
 		}
+
+		if s.StrictIdentifiers && !strictIdentifier.MatchString(part) {
+			s.setErr(errors.New("identifier: " + strconv.Quote(part) + " is not allowed in strict mode"))
+			return dialectFormat + dialectFormat
+		}
+
+		finalSchemaStmt += dialectFormat + strings.ReplaceAll(part, dialectFormat, dialectFormat+dialectFormat) + dialectFormat + `.`
 	}
 
 	return strings.TrimSuffix(finalSchemaStmt, `.`)
 }
 
+// strictIdentifier is the only identifier format allowed when StrictIdentifiers is set
+var strictIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// splitIdentifier splits a dotted identifier into its unquoted parts, dots inside quoted parts do not split and doubled
+// quotes inside quoted parts are unescaped. An unquoted * is returned as is
+func splitIdentifier(identifier string, quote byte) ([]string, error) {
+	var parts []string
+
+	identifier = strings.TrimSpace(identifier)
+
+	for i := 0; ; {
+		for i < len(identifier) && identifier[i] == ' ' {
+			i++
+		}
+
+		part := ``
+
+		if i < len(identifier) && identifier[i] == quote {
+			var sb strings.Builder
+			closed := false
+
+			for i++; i < len(identifier); i++ {
+				if identifier[i] == quote {
+					if i+1 < len(identifier) && identifier[i+1] == quote {
+						sb.WriteByte(quote)
+						i++
+						continue
+					}
+
+					closed = true
+					i++
+					break
+				}
+
+				// raw bytes so multi byte characters are copied as they are
+				sb.WriteByte(identifier[i])
+			}
+
+			part = sb.String()
+
+			if !closed {
+				return nil, errors.New("identifier: " + strconv.Quote(identifier) + " has an unterminated quote")
+			}
+
+			for i < len(identifier) && identifier[i] == ' ' {
+				i++
+			}
+
+			if i < len(identifier) && identifier[i] != '.' {
+				return nil, errors.New("identifier: " + strconv.Quote(identifier) + " has characters after a closing quote")
+			}
+		} else {
+			end := strings.IndexByte(identifier[i:], '.')
+			if end == -1 {
+				end = len(identifier) - i
+			}
+
+			part = strings.TrimSpace(identifier[i : i+end])
+			i += end
+		}
+
+		if part == `` {
+			return nil, errors.New("identifier: " + strconv.Quote(identifier) + " has an empty part")
+		}
+
+		for _, r := range part {
+			if unicode.IsControl(r) {
+				return nil, errors.New("identifier: " + strconv.Quote(identifier) + " contains a control character")
+			}
+		}
+
+		parts = append(parts, part)
+
+		if i >= len(identifier) {
+			return parts, nil
+		}
+
+		// skip the dot
+		i++
+	}
+}
+
 // used to ensure the correct formatting for the ON part of a join query
 func (s *Sqlbuilder) formatJoinOn(joinStmt string) string {
 	joinParts := strings.Split(joinStmt, "=")
@@ -1,10 +1,18 @@
 package pqb
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"math"
+	"math/big"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/SamuelBanksTech/Go-Postgresql-Query-Builder/pqbHelpers"
 )
 
 func TestSqlbuilder_From_and_Build(t *testing.T) {
@@ -206,6 +214,21 @@ func TestSqlbuilder_WhereIn(t *testing.T) {
 
 }
 
+func TestSqlbuilder_WhereStringMatch_quotes(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	_, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereStringMatchAny(`mycol`, []string{` O'Brien `}).
+		WhereStringMatchAll(`mycol`, []string{`50%_off`}).
+		Build()
+
+	wantArgs := []interface{}{`%O'Brien%`, `%50\%\_off%`}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
 func TestSqlbuilder_WhereStringMatchAny(t *testing.T) {
 	var sqlb Sqlbuilder
 
@@ -366,7 +389,7 @@ func TestSqlbuilder_BuildInsert_and_mapping(t *testing.T) {
 	}
 
 	wantSql := `INSERT INTO "myschema"."mytable" ("string_col", "scnn", "int_col", "int8_col", "int32_col", "int64_col", "float64_col", "float32_col", "time_col", "bool_col") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) ON CONFLICT DO NOTHING`
-	wantArgs := []string{`'mystring'`, `'myscnnstring'`, `1`, `2`, `3`, `4`, `1.100000`, `1.200000`, nowTime.Format("2006-01-02 15:04:05"), `TRUE`}
+	wantArgs := []interface{}{`mystring`, `myscnnstring`, int64(1), int64(2), int64(3), int64(4), 1.1, 1.2, nowTime, true}
 
 	if gotSql != wantSql {
 		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
@@ -378,7 +401,557 @@ func TestSqlbuilder_BuildInsert_and_mapping(t *testing.T) {
 
 	for i, v := range wantArgs {
 		if gotArgs[i] != v {
-			t.Errorf("argument mismatch got ")
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+type mockValuer struct {
+	val string
+}
+
+func (m mockValuer) Value() (driver.Value, error) {
+	return m.val, nil
+}
+
+func TestSqlbuilder_BuildUpdate_nullable_mapping(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	age := 30
+	mockValue := mockValuer{`abc`}
+	nullString := sql.NullString{String: `def`, Valid: true}
+
+	mockStruct := struct {
+		NilCol       *string
+		PtrCol       *int
+		NullCol      sql.NullString
+		NullInvalid  sql.NullInt64
+		ValuerCol    mockValuer
+		NilValuerCol *mockValuer
+	}{
+		nil,
+		&age,
+		nullString,
+		sql.NullInt64{},
+		mockValue,
+		nil,
+	}
+
+	gotSql, gotArgs, err := sqlb.Where(`id`, `=`, `1`).BuildUpdate(`myschema.mytable`, mockStruct)
+	if err != nil {
+		t.Error(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "nil_col" = $2, "ptr_col" = $3, "null_col" = $4, "null_invalid" = $5, "valuer_col" = $6, "nil_valuer_col" = $7 WHERE "id" = $1 `
+	wantArgs := []interface{}{`1`, nil, int64(30), nullString, sql.NullInt64{}, mockValue, nil}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("length of args incorrect: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+type mockTaggedStruct struct {
+	ID        int       `pqb:"id,pk"`
+	Name      string    `pqb:"display_name"`
+	Nickname  string    `pqb:",omitempty"`
+	CreatedAt time.Time `pqb:"created_at,readonly"`
+	Internal  []string  `pqb:"-"`
+}
+
+func TestSqlbuilder_BuildInsert_tag_options(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.BuildInsert(`myschema.mytable`, mockTaggedStruct{Name: `bob`, CreatedAt: time.Now()}, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `INSERT INTO "myschema"."mytable" ("display_name") VALUES ($1) `
+	wantArgs := []interface{}{`bob`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("length of args incorrect: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_BuildUpdate_tag_options(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.BuildUpdate(`myschema.mytable`, mockTaggedStruct{ID: 7, Name: `bob`, Nickname: `bobby`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "display_name" = $1, "nickname" = $2 WHERE "id" = $3 `
+	wantArgs := []interface{}{`bob`, `bobby`, int64(7)}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("length of args incorrect: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+type mockTimestamps struct {
+	CreatedAt time.Time `pqb:"created_at,readonly"`
+	UpdatedAt time.Time
+}
+
+type mockAddress struct {
+	Line1    string
+	Postcode string
+}
+
+type mockNestedStruct struct {
+	mockTimestamps
+	Name     string
+	Billing  mockAddress            `pqb:",prefix=billing_"`
+	Shipping mockAddress            `pqb:",inline"`
+	Meta     map[string]interface{} `pqb:"meta,json"`
+	Settings *mockAddress           `pqb:",json"`
+}
+
+func TestSqlbuilder_BuildInsert_nested_mapping(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	updated := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockStruct := mockNestedStruct{
+		mockTimestamps: mockTimestamps{UpdatedAt: updated},
+		Name:           `bob`,
+		Billing:        mockAddress{`1 street`, `AB1`},
+		Shipping:       mockAddress{`2 street`, `CD2`},
+		Meta:           map[string]interface{}{`vip`: true},
+	}
+
+	gotSql, gotArgs, err := sqlb.BuildInsert(`myschema.mytable`, mockStruct, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `INSERT INTO "myschema"."mytable" ("updated_at", "name", "billing_line1", "billing_postcode", "line1", "postcode", "meta", "settings") VALUES ($1, $2, $3, $4, $5, $6, $7, $8) `
+	wantArgs := []interface{}{updated, `bob`, `1 street`, `AB1`, `2 street`, `CD2`, `{"vip":true}`, nil}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("length of args incorrect: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_BuildInsert_native_args(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	mockStruct := struct {
+		Quote   string
+		Precise float64
+		Bytes   []byte
+	}{
+		`it's`,
+		1.123456789,
+		[]byte(`raw`),
+	}
+
+	_, gotArgs, err := sqlb.BuildInsert(`myschema.mytable`, mockStruct, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantArgs := []interface{}{`it's`, 1.123456789, []byte(`raw`)}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+type mockStatus string
+
+type mockMoney struct {
+	pence int64
+}
+
+func TestSqlbuilder_BuildInsert_kind_mapping(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	pqbHelpers.RegisterConverter(mockMoney{}, func(v interface{}) (interface{}, error) {
+		return v.(mockMoney).pence, nil
+	})
+
+	mockStruct := struct {
+		Status  mockStatus
+		Small   int16
+		Unsign  uint32
+		Big     *big.Int
+		UUID    [16]byte `pqb:"uuid,type=uuid"`
+		Hash    [16]byte
+		Tags    []string
+		Balance mockMoney
+	}{
+		`active`,
+		12,
+		34,
+		big.NewInt(56),
+		[16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+		[16]byte{1, 2},
+		[]string{`a`, `b`},
+		mockMoney{199},
+	}
+
+	_, gotArgs, err := sqlb.BuildInsert(`myschema.mytable`, mockStruct, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantArgs := []interface{}{`active`, int64(12), int64(34), `56`, `123e4567-e89b-12d3-a456-426614174000`, []byte{1, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, `{"a","b"}`, int64(199)}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	_, _, err = sqlb.BuildInsert(`myschema.mytable`, struct{ Huge uint64 }{math.MaxUint64}, ``)
+	if err == nil {
+		t.Error(`expected an overflow error for uint64`)
+	}
+}
+
+func TestSqlbuilder_BuildInsert_pointer_and_slice(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.BuildInsert(`myschema.mytable`, &mockTaggedStruct{Name: `bob`}, ``)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `INSERT INTO "myschema"."mytable" ("display_name") VALUES ($1) `
+	wantArgs := []interface{}{`bob`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	rows := []*mockTaggedStruct{
+		{Name: `bob`},
+		{ID: 5, Name: `alice`, Nickname: `al`},
+	}
+
+	gotSql, gotArgs, err = sqlb.BuildInsert(`myschema.mytable`, rows, `ON CONFLICT DO NOTHING`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql = `INSERT INTO "myschema"."mytable" ("id", "display_name", "nickname") VALUES (DEFAULT, $1, DEFAULT), ($2, $3, $4) ON CONFLICT DO NOTHING`
+	wantArgs = []interface{}{`bob`, int64(5), `alice`, `al`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_Build_invalid_data(t *testing.T) {
+	var sqlb Sqlbuilder
+	var nilStruct *mockTaggedStruct
+
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{`int`, 1},
+		{`nil`, nil},
+		{`nil pointer`, nilStruct},
+		{`empty slice`, []mockTaggedStruct{}},
+		{`slice of ints`, []int{1}},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := sqlb.BuildInsert(`myschema.mytable`, tt.data, ``); err == nil {
+			t.Errorf("%v: BuildInsert expected an error", tt.name)
+		}
+
+		if _, _, err := sqlb.Where(`id`, `=`, `1`).BuildUpdate(`myschema.mytable`, tt.data); err == nil {
+			t.Errorf("%v: BuildUpdate expected an error", tt.name)
+		}
+	}
+
+	if _, _, err := sqlb.Where(`id`, `=`, `1`).BuildUpdate(`myschema.mytable`, []mockTaggedStruct{{Name: `bob`}}); err == nil {
+		t.Error(`BuildUpdate expected an error for a slice`)
+	}
+}
+
+func TestSqlbuilder_BuildInsert_naming_strategy(t *testing.T) {
+	mockStruct := struct {
+		HTTPStatusCode int
+		UserIDs        []int64
+		PAID           bool
+		Name           string `pqb:"display_name"`
+	}{200, []int64{1}, true, `bob`}
+
+	tests := []struct {
+		naming  pqbHelpers.NamingStrategy
+		wantSql string
+	}{
+		{nil, `INSERT INTO "mytable" ("http_status_code", "user_i_ds", "paid", "display_name") VALUES ($1, $2, $3, $4) `},
+		{pqbHelpers.SnakeCase{Acronyms: []string{`HTTP`, `ID`}}, `INSERT INTO "mytable" ("http_status_code", "user_ids", "paid", "display_name") VALUES ($1, $2, $3, $4) `},
+		{pqbHelpers.CamelCase{Acronyms: []string{`HTTP`, `ID`}}, `INSERT INTO "mytable" ("httpStatusCode", "userIds", "paid", "display_name") VALUES ($1, $2, $3, $4) `},
+		{pqbHelpers.LowerCase{}, `INSERT INTO "mytable" ("httpstatuscode", "userids", "paid", "display_name") VALUES ($1, $2, $3, $4) `},
+		{pqbHelpers.NamingFunc(strings.ToUpper), `INSERT INTO "mytable" ("HTTPSTATUSCODE", "USERIDS", "PAID", "display_name") VALUES ($1, $2, $3, $4) `},
+	}
+
+	for _, tt := range tests {
+		sqlb := Sqlbuilder{Naming: tt.naming}
+
+		gotSql, _, err := sqlb.BuildInsert(`mytable`, mockStruct, ``)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if gotSql != tt.wantSql {
+			t.Errorf("got %v \nwanted %v", gotSql, tt.wantSql)
+		}
+	}
+}
+
+func TestSqlbuilder_BuildInsert_naming_strategy_unicode(t *testing.T) {
+	mockStruct := struct {
+		ÑameID  int
+		ÉtatURL string
+	}{1, `x`}
+
+	tests := []struct {
+		naming  pqbHelpers.NamingStrategy
+		wantSql string
+	}{
+		{pqbHelpers.SnakeCase{Acronyms: []string{`ID`, `URL`}}, `INSERT INTO "mytable" ("ñame_id", "état_url") VALUES ($1, $2) `},
+		{pqbHelpers.CamelCase{Acronyms: []string{`ID`, `URL`}}, `INSERT INTO "mytable" ("ñameId", "étatUrl") VALUES ($1, $2) `},
+	}
+
+	for _, tt := range tests {
+		sqlb := Sqlbuilder{Naming: tt.naming}
+
+		gotSql, _, err := sqlb.BuildInsert(`mytable`, mockStruct, ``)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if gotSql != tt.wantSql {
+			t.Errorf("got %v \nwanted %v", gotSql, tt.wantSql)
+		}
+	}
+}
+
+func TestSqlbuilder_missing_where_guard(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.DeleteFrom(`myschema.mytable`).Build()
+
+	if gotSql != `` {
+		t.Errorf("got %v \nwanted an empty query", gotSql)
+	}
+
+	if sqlb.Err() != ErrMissingWhere {
+		t.Errorf("got %v \nwanted %v", sqlb.Err(), ErrMissingWhere)
+	}
+
+	gotSql, _ = sqlb.AllowFullTable().Build()
+	wantSql := `DELETE FROM "myschema"."mytable"`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if sqlb.Err() != nil {
+		t.Errorf("unexpected error %v", sqlb.Err())
+	}
+
+	sqlb.Reset()
+
+	if _, _, err := sqlb.BuildUpdate(`myschema.mytable`, mockAddress{Line1: `1 street`}); err != ErrMissingWhere {
+		t.Errorf("got %v \nwanted %v", err, ErrMissingWhere)
+	}
+
+	gotSql, _, err := sqlb.AllowFullTable().BuildUpdate(`myschema.mytable`, mockAddress{Line1: `1 street`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql = `UPDATE "myschema"."mytable" SET "line1" = $1, "postcode" = $2 `
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if _, _, err := sqlb.BuildUpdate(`myschema.mytable`, mockAddress{Line1: `1 street`}); err != ErrMissingWhere {
+		t.Errorf("AllowFullTable should be cleared after a build: got %v", err)
+	}
+}
+
+func TestSqlbuilder_identifier_escaping(t *testing.T) {
+	tests := []struct {
+		identifier string
+		dialect    string
+		want       string
+	}{
+		{`myschema.mytable`, ``, `"myschema"."mytable"`},
+		{` mytable.* `, ``, `"mytable".*`},
+		{`"my.schema"."my""table"`, ``, `"my.schema"."my""table"`},
+		{`my"col`, ``, `"my""col"`},
+		{`my"col" OR 1=1 --`, ``, `"my""col"" OR 1=1 --"`},
+		{"my`col", `mysql`, "`my``col`"},
+		{`"tëst"."naïve"`, ``, `"tëst"."naïve"`},
+		{`"日本""語"`, ``, `"日本""語"`},
+	}
+
+	for _, tt := range tests {
+		sqlb := Sqlbuilder{Dialect: tt.dialect}
+
+		got := sqlb.formatSchema(tt.identifier)
+
+		if got != tt.want {
+			t.Errorf("got %v \nwanted %v", got, tt.want)
+		}
+
+		if sqlb.Err() != nil {
+			t.Errorf("%v: unexpected error %v", tt.identifier, sqlb.Err())
+		}
+	}
+}
+
+func TestSqlbuilder_identifier_non_ascii(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`"tëst"`).Select(`"naïve"`).Build()
+	wantSql := `SELECT "naïve" FROM "tëst"`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+}
+
+func TestSqlbuilder_identifier_validation(t *testing.T) {
+	invalid := []string{``, `myschema.`, `.mytable`, `my..table`, "my\ntable", `"unterminated`, `"quoted"extra`}
+
+	for _, identifier := range invalid {
+		var sqlb Sqlbuilder
+
+		gotSql, _ := sqlb.From(`myschema.mytable`).OrderBy(identifier, `ASC`).Build()
+
+		if gotSql != `` {
+			t.Errorf("%q: got %v \nwanted an empty query", identifier, gotSql)
 		}
+
+		if sqlb.Err() == nil {
+			t.Errorf("%q: expected an error", identifier)
+		}
+	}
+
+	sqlb := Sqlbuilder{StrictIdentifiers: true}
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).Select(`id`, `t.*`, `"$col"`).Build()
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("strict mode should refuse $col: got %v", gotSql)
+	}
+
+	gotSql, _ = sqlb.Reset().From(`myschema.mytable`).Select(`_id`, `t.*`, `col$1`).Build()
+	wantSql := `SELECT "_id", "t".*, "col$1" FROM "myschema"."mytable"`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if _, _, err := sqlb.BuildInsert(`my table`, mockAddress{}, ``); err == nil {
+		t.Error(`strict mode should refuse "my table" in BuildInsert`)
+	}
+}
+
+func TestSqlbuilder_BuildInsert_default_values(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	type onlyDefaults struct {
+		ID      int    `pqb:"id,pk"`
+		Note    string `pqb:"note,omitempty"`
+		Created string `pqb:"created_at,readonly"`
+	}
+
+	gotSql, gotArgs, err := sqlb.BuildInsert(`myschema.mytable`, onlyDefaults{}, `RETURNING id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `INSERT INTO "myschema"."mytable" DEFAULT VALUES RETURNING id`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != 0 {
+		t.Errorf("expected no args, got %v", gotArgs)
+	}
+
+	type onlyReadonly struct {
+		Created string `pqb:"created_at,readonly"`
+	}
+
+	_, _, err = sqlb.BuildInsert(`myschema.mytable`, []onlyReadonly{{}, {}}, ``)
+	if err == nil {
+		t.Error(`expected an error for rows with no columns to insert`)
+	}
+}
+
+func TestSqlbuilder_OrderBy_replaces(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		OrderBy(`name`, `ASC`).
+		OrderBy(`created_at`, `DESC`).
+		ThenOrderBy(`id`, `ASC`).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" ORDER BY "created_at" DESC, "id" ASC`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
 	}
 }
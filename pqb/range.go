@@ -0,0 +1,129 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// typeName is a plain type name used in a cast e.g. tstzrange or bigint
+var typeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RangeBounds sets which ends of a Range are inclusive, "[" or "]" are inclusive and "(" or ")" are exclusive
+type RangeBounds string
+
+const (
+	// RangeInclusiveExclusive includes the lower bound but not the upper, this is the postgres canonical form and the default
+	RangeInclusiveExclusive RangeBounds = "[)"
+	// RangeInclusive includes both bounds
+	RangeInclusive RangeBounds = "[]"
+	// RangeExclusive excludes both bounds
+	RangeExclusive RangeBounds = "()"
+	// RangeExclusiveInclusive excludes the lower bound but includes the upper
+	RangeExclusiveInclusive RangeBounds = "(]"
+)
+
+// Range is a postgres range value (tstzrange, daterange, int4range etc) that is bound as a range literal argument
+// A nil Lower or Upper is unbounded, Type is optional and if set the argument is cast to it e.g. `tstzrange`, a Type that
+// is not a plain type name is recorded for Err and the query will not build
+type Range struct {
+	Lower  interface{}
+	Upper  interface{}
+	Bounds RangeBounds
+	Type   string
+}
+
+// String returns the range in the postgres range literal format e.g. [2022-01-01T00:00:00Z,2022-02-01T00:00:00Z)
+func (r Range) String() string {
+	bounds := r.Bounds
+	switch bounds {
+	case RangeInclusive, RangeExclusive, RangeExclusiveInclusive:
+	default:
+		bounds = RangeInclusiveExclusive
+	}
+
+	return string(bounds[0]) + rangeElement(r.Lower) + `,` + rangeElement(r.Upper) + string(bounds[1])
+}
+
+// WhereRangeContains matches rows where the range column contains the value (postgres @>)
+// value can be a single element (e.g. a time.Time or int) or a Range, a single element is bound as the range holding only
+// that point (e.g. [5,5]) so postgres reads it as the column's range type whatever the element type is
+// Usage "xxx.From(`myschema.bookings`).WhereRangeContains(`during`, time.Now())"
+func (s *Sqlbuilder) WhereRangeContains(column string, value interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	r, ok := value.(Range)
+	if !ok {
+		r = Range{Lower: value, Upper: value, Bounds: RangeInclusive}
+	}
+
+	return s.whereRange(column, `@>`, r)
+}
+
+// WhereRangeContainedBy matches rows where the column (a range or a single element) is within the range (postgres <@)
+// Usage "xxx.From(`myschema.bookings`).WhereRangeContainedBy(`booked_on`, pqb.Range{Lower: start, Upper: end, Type: `daterange`})"
+func (s *Sqlbuilder) WhereRangeContainedBy(column string, r Range) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereRange(column, `<@`, r)
+}
+
+// WhereRangeOverlaps matches rows where the range column has any points in common with the range (postgres &&)
+// Usage "xxx.From(`myschema.bookings`).WhereRangeOverlaps(`during`, pqb.Range{Lower: start, Upper: end})"
+func (s *Sqlbuilder) WhereRangeOverlaps(column string, r Range) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereRange(column, `&&`, r)
+}
+
+// WhereRangeAdjacent matches rows where the range column is next to but does not overlap the range (postgres -|-)
+// Usage "xxx.From(`myschema.bookings`).WhereRangeAdjacent(`during`, pqb.Range{Lower: start, Upper: end})"
+func (s *Sqlbuilder) WhereRangeAdjacent(column string, r Range) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.whereRange(column, `-|-`, r)
+}
+
+func (s *Sqlbuilder) whereRange(column string, operator string, r Range) *Sqlbuilder {
+	arg := s.storeArg(r.String())
+	if r.Type != `` {
+		if !typeName.MatchString(r.Type) {
+			s.setErr(errors.New("range type: " + strconv.Quote(r.Type) + " is not a valid type name"))
+		}
+
+		arg += `::` + r.Type
+	}
+
+	return s.WhereRaw(s.formatColumn(column) + ` ` + operator + ` ` + arg)
+}
+
+// rangeElement formats a single bound of a range literal, strings are double quoted so commas and brackets are safe
+func rangeElement(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ``
+	case time.Time:
+		return `"` + v.Format(time.RFC3339Nano) + `"`
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		str := strings.ReplaceAll(fmt.Sprint(v), `\`, `\\`)
+		return `"` + strings.ReplaceAll(str, `"`, `\"`) + `"`
+	}
+}
@@ -0,0 +1,98 @@
+package pqb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRange_String(t *testing.T) {
+	start := time.Date(2022, 1, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		r    Range
+		want string
+	}{
+		{Range{Lower: 1, Upper: 10}, `[1,10)`},
+		{Range{Lower: 1, Upper: 10, Bounds: RangeInclusive}, `[1,10]`},
+		{Range{Lower: nil, Upper: 10, Bounds: RangeExclusive}, `(,10)`},
+		{Range{Lower: start, Bounds: RangeExclusiveInclusive}, `("2022-01-01T09:30:00Z",]`},
+		{Range{Lower: `a,"b`, Upper: `c\d`}, `["a,\"b","c\\d")`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.String(); got != tt.want {
+			t.Errorf("got %v \nwanted %v", got, tt.want)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereRange(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	gotSql, gotArgs := sqlb.From(`myschema.bookings`).
+		WhereRangeContains(`during`, start).
+		WhereRangeContains(`during`, Range{Lower: start, Upper: end, Type: `tstzrange`}).
+		WhereRangeContainedBy(`seats`, Range{Lower: 1, Upper: 5, Bounds: RangeInclusive}).
+		WhereRangeOverlaps(`during`, Range{Lower: start, Upper: end}).
+		WhereRangeAdjacent(`during`, Range{Upper: start}).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."bookings" WHERE "during" @> $1 AND "during" @> $2::tstzrange AND "seats" <@ $3 AND "during" && $4 AND "during" -|- $5`
+	wantArgs := []interface{}{
+		`["2022-01-01T00:00:00Z","2022-01-01T00:00:00Z"]`,
+		`["2022-01-01T00:00:00Z","2022-01-02T00:00:00Z")`,
+		`[1,5]`,
+		`["2022-01-01T00:00:00Z","2022-01-02T00:00:00Z")`,
+		`[,"2022-01-01T00:00:00Z")`,
+	}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereRange_invalid_type(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.bookings`).
+		WhereRangeOverlaps(`during`, Range{Lower: 1, Upper: 5, Type: `int4range); DROP TABLE x; --`}).
+		Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for an invalid range type: got %v", gotSql)
+	}
+}
+
+func TestSqlbuilder_WhereRangeContains_element(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.rooms`).
+		WhereRangeContains(`seats`, 5).
+		WhereRangeContains(`code`, `b,c`).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."rooms" WHERE "seats" @> $1 AND "code" @> $2`
+	wantArgs := []interface{}{`[5,5]`, `["b,c","b,c"]`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
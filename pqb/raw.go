@@ -0,0 +1,108 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WhereRawArgs is WhereRaw with bound parameters, each ? in the fragment is replaced with the next placeholder
+// ($n numbered after any existing args) and its arg is added to the query args. Use ?? for a literal ? (e.g. jsonb operators)
+// a ? inside a quoted string or identifier is left alone. If the number of markers and args differ the query will not build
+// Named parameters such as :status can also be used, their values are given later to BuildNamed
+// Usage "xxx.From(`myschema.mytable`).WhereRawArgs(`(price * ?) > ?`, 1.2, 100)"
+func (s *Sqlbuilder) WhereRawArgs(whereStmt string, args ...interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.WhereRaw(s.bindRaw(whereStmt, args))
+}
+
+// SelectRawArgs is SelectRaw with bound parameters, see WhereRawArgs for the ? marker rules
+// Usage "xxx.From(`myschema.mytable`).SelectRawArgs(`CASE WHEN score > ? THEN 'high' ELSE 'low' END AS band`, 50)"
+func (s *Sqlbuilder) SelectRawArgs(selectStmt string, args ...interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	re := regexp.MustCompile(`\r?\n`)
+	selectStmt = re.ReplaceAllString(selectStmt, " ")
+
+	s.selectStmt += s.bindRaw(selectStmt, args) + `, `
+	return s
+}
+
+// namedPrefix are the characters a :name parameter can follow
+const namedPrefix = " \t\r\n(,=<>!+-*/%|&^~"
+
+// bindRaw replaces the ? markers in a raw fragment with placeholders for the args, and :name with a named parameter
+// where a value is expected (at the start or after whitespace, an opening bracket, a comma or an operator) so array
+// slices such as tags[lo:hi] are left alone
+func (s *Sqlbuilder) bindRaw(fragment string, args []interface{}) string {
+	var sb strings.Builder
+	var quote byte
+	bound := 0
+	brackets := 0
+
+	for i := 0; i < len(fragment); i++ {
+		c := fragment[i]
+
+		switch {
+		case quote != 0:
+			// a doubled quote is an escaped quote so simply toggling in and out again is correct
+			if c == quote {
+				quote = 0
+			}
+			sb.WriteByte(c)
+		case c == '\'' || c == '"':
+			quote = c
+			sb.WriteByte(c)
+		case c == '[':
+			brackets++
+			sb.WriteByte(c)
+		case c == ']':
+			if brackets > 0 {
+				brackets--
+			}
+			sb.WriteByte(c)
+		case c == '?' && i+1 < len(fragment) && fragment[i+1] == '?':
+			sb.WriteByte('?')
+			i++
+		case c == '?':
+			if bound < len(args) {
+				sb.WriteString(s.storeArg(args[bound]))
+			}
+			bound++
+		case c == ':' && i+1 < len(fragment) && fragment[i+1] == ':':
+			// a postgres :: cast
+			sb.WriteString(`::`)
+			i++
+		case c == ':' && brackets == 0 && i+1 < len(fragment) && isNameStart(fragment[i+1]) && (i == 0 || strings.IndexByte(namedPrefix, fragment[i-1]) >= 0):
+			end := i + 1
+			for end < len(fragment) && isNamePart(fragment[end]) {
+				end++
+			}
+			sb.WriteString(s.storeNamed(fragment[i+1 : end]))
+			i = end - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	if bound != len(args) {
+		s.setErr(errors.New("raw: " + strconv.Itoa(bound) + " ? markers but " + strconv.Itoa(len(args)) + " args in " + strconv.Quote(fragment)))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package pqb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqlbuilder_WhereRawArgs_SelectRawArgs(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		Select(`id`).
+		SelectRawArgs(`CASE WHEN score > ? THEN 'high?' ELSE 'low' END AS band`, 50).
+		Where(`active`, `=`, `true`).
+		WhereRawArgs(`(price * ?) > ?`, 1.2, 100).
+		WhereRawArgs(`data ?? 'key' AND "odd?col" = ?`, `x`).
+		Build()
+
+	wantSql := `SELECT "id", CASE WHEN score > $1 THEN 'high?' ELSE 'low' END AS band FROM "myschema"."mytable" WHERE "active" = $2 AND (price * $3) > $4 AND data ? 'key' AND "odd?col" = $5`
+	wantArgs := []interface{}{50, `true`, 1.2, 100, `x`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_WhereRawArgs_mismatch(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).WhereRawArgs(`a = ? AND b = ?`, 1).Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for too few args: got %v", gotSql)
+	}
+
+	gotSql, _ = sqlb.Reset().From(`myschema.mytable`).WhereRawArgs(`a = ?`, 1, 2).Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for too many args: got %v", gotSql)
+	}
+}
+
+func TestSqlbuilder_WhereRawArgs_colons(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.From(`myschema.mytable`).
+		WhereRawArgs(`tags[lo:hi] = ? AND tags[1:n] <> ?`, 1, 2).
+		WhereRawArgs(`created_at::date = :day AND (owner_id=:user OR 'a:b' = x:y)`).
+		BuildNamed(map[string]interface{}{"day": `2024-01-01`, "user": 7})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE tags[lo:hi] = $1 AND tags[1:n] <> $2 AND created_at::date = $3 AND (owner_id=$4 OR 'a:b' = x:y)`
+	wantArgs := []interface{}{1, 2, `2024-01-01`, 7}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"regexp"
+	"strings"
+)
+
+var selectAliasRe = regexp.MustCompile(`(?is)^(.+?)\s+AS\s+("[^"]*"|\S+)$`)
+var selectAggregateRe = regexp.MustCompile(`(?is)^(COUNT|SUM|AVG|MIN|MAX)\s*\(\s*(DISTINCT\s+)?(.+?)\s*\)$`)
+
+// SelectAs select a column with an alias
+// Usage "xxx.From(`myschema.mytable`).SelectAs(`u.name`, `author`)"
+func (s *Sqlbuilder) SelectAs(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	s.selectStmt += s.withAlias(s.formatSchema(column), alias) + `, `
+
+	return s
+}
+
+// SelectCount select the COUNT of a column (or * for all rows), alias can be left empty
+// Usage "xxx.From(`myschema.mytable`).SelectCount(`*`, `total`)"
+func (s *Sqlbuilder) SelectCount(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.selectAggregate(`COUNT`, ``, column, alias)
+}
+
+// SelectCountDistinct select the number of distinct values of a column, alias can be left empty
+// Usage "xxx.From(`myschema.orders`).SelectCountDistinct(`customer_id`, `customers`)"
+func (s *Sqlbuilder) SelectCountDistinct(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.selectAggregate(`COUNT`, `DISTINCT `, column, alias)
+}
+
+// SelectSum select the SUM of a column, alias can be left empty
+// Usage "xxx.From(`myschema.orders`).SelectSum(`total`, `revenue`)"
+func (s *Sqlbuilder) SelectSum(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.selectAggregate(`SUM`, ``, column, alias)
+}
+
+// SelectAvg select the AVG of a column, alias can be left empty
+// Usage "xxx.From(`myschema.orders`).SelectAvg(`total`, `average_order`)"
+func (s *Sqlbuilder) SelectAvg(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.selectAggregate(`AVG`, ``, column, alias)
+}
+
+// SelectMin select the MIN of a column, alias can be left empty
+// Usage "xxx.From(`myschema.orders`).SelectMin(`created_at`, `first_order`)"
+func (s *Sqlbuilder) SelectMin(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.selectAggregate(`MIN`, ``, column, alias)
+}
+
+// SelectMax select the MAX of a column, alias can be left empty
+// Usage "xxx.From(`myschema.orders`).SelectMax(`created_at`, `last_order`)"
+func (s *Sqlbuilder) SelectMax(column string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.selectAggregate(`MAX`, ``, column, alias)
+}
+
+// GroupBy group the returned rows by one or more columns, used with the aggregate selects
+// Usage "xxx.From(`myschema.orders`).Select(`customer_id`).SelectSum(`total`, `revenue`).GroupBy(`customer_id`)"
+func (s *Sqlbuilder) GroupBy(columns ...string) *Sqlbuilder {
+	s = s.mutate()
+
+	for _, column := range columns {
+		s.groupbyStmt += s.formatSchema(column) + `, `
+	}
+
+	return s
+}
+
+func (s *Sqlbuilder) selectAggregate(function string, distinct string, column string, alias string) *Sqlbuilder {
+	s.selectStmt += s.withAlias(s.formatAggregate(function, distinct, column), alias) + `, `
+
+	return s
+}
+
+// formatSelect formats a Select argument, recognising `col AS alias` and simple aggregates e.g. `count(DISTINCT u.id) AS users`
+func (s *Sqlbuilder) formatSelect(selectStmt string) string {
+	selectStmt = strings.TrimSpace(selectStmt)
+	alias := ``
+
+	if m := selectAliasRe.FindStringSubmatch(selectStmt); m != nil {
+		selectStmt, alias = m[1], m[2]
+	}
+
+	if m := selectAggregateRe.FindStringSubmatch(selectStmt); m != nil {
+		distinct := ``
+		if m[2] != `` {
+			distinct = `DISTINCT `
+		}
+
+		return s.withAlias(s.formatAggregate(strings.ToUpper(m[1]), distinct, m[3]), alias)
+	}
+
+	return s.withAlias(s.formatSchema(selectStmt), alias)
+}
+
+func (s *Sqlbuilder) formatAggregate(function string, distinct string, column string) string {
+	if strings.TrimSpace(column) == `*` {
+		return function + `(*)`
+	}
+
+	return function + `(` + distinct + s.formatSchema(column) + `)`
+}
+
+func (s *Sqlbuilder) withAlias(selectStmt string, alias string) string {
+	if alias == `` {
+		return selectStmt
+	}
+
+	return selectStmt + ` AS ` + s.formatSchema(alias)
+}
@@ -0,0 +1,41 @@
+package pqb
+
+import (
+	"testing"
+)
+
+func TestSqlbuilder_Select_alias_and_aggregates(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		Select(`u.name AS author`, `count(*) AS total`, `sum(u.score) as "Total Score"`, `Count(DISTINCT u.id)`, `mycol`).
+		Build()
+
+	wantSql := `SELECT "u"."name" AS "author", COUNT(*) AS "total", SUM("u"."score") AS "Total Score", COUNT(DISTINCT "u"."id"), "mycol" FROM "myschema"."mytable"`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+}
+
+func TestSqlbuilder_SelectAs_aggregate_helpers_GroupBy(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.orders`).
+		SelectAs(`o.customer_id`, `customer`).
+		SelectCount(`*`, `orders`).
+		SelectCountDistinct(`o.product_id`, `products`).
+		SelectSum(`o.total`, `revenue`).
+		SelectAvg(`o.total`, ``).
+		SelectMin(`o.created_at`, `first_order`).
+		SelectMax(`o.created_at`, `last_order`).
+		GroupBy(`o.customer_id`).
+		OrderBy(`revenue`, `DESC`).
+		Build()
+
+	wantSql := `SELECT "o"."customer_id" AS "customer", COUNT(*) AS "orders", COUNT(DISTINCT "o"."product_id") AS "products", SUM("o"."total") AS "revenue", AVG("o"."total"), MIN("o"."created_at") AS "first_order", MAX("o"."created_at") AS "last_order" FROM "myschema"."orders" GROUP BY "o"."customer_id" ORDER BY "revenue" DESC`
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+}
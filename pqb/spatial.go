@@ -0,0 +1,107 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"strconv"
+)
+
+// GeoCast is the PostGIS type that geo columns and points are cast to before comparison
+type GeoCast string
+
+const (
+	// CastGeography distances are in metres on the spheroid, this is the default
+	CastGeography GeoCast = "geography"
+	// CastGeometry distances are in the units of the SRID (degrees for 4326), faster but less accurate
+	CastGeometry GeoCast = "geometry"
+)
+
+// geoSRID is the spatial reference used for all longitude / latitude input (WGS 84)
+const geoSRID = 4326
+
+// WhereWithinDistance matches rows where the geo column is within the distance of the point using ST_DWithin
+// Usage "xxx.From(`myschema.stores`).WhereWithinDistance(`location`, -0.1276, 51.5072, 5000)"
+func (s *Sqlbuilder) WhereWithinDistance(geoColumn string, lon float64, lat float64, metres float64) *Sqlbuilder {
+	s = s.mutate()
+
+	s.setGeoPoint(geoColumn, lon, lat)
+
+	return s.WhereRaw(`ST_DWithin(` + s.geoColumn + `, ` + s.geoPoint + `, ` + s.storeArg(metres) + `)`)
+}
+
+// WhereIntersects matches rows where the geo column intersects the WKT shape e.g. `POLYGON((...))`
+// Usage "xxx.From(`myschema.regions`).WhereIntersects(`boundary`, `POINT(-0.1276 51.5072)`)"
+func (s *Sqlbuilder) WhereIntersects(geoColumn string, wkt string) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.WhereRaw(`ST_Intersects(` + s.geoCastColumn(geoColumn) + `, ST_GeomFromText(` + s.storeArg(wkt) + `, ` + strconv.Itoa(geoSRID) + `)::` + s.geoCast() + `)`)
+}
+
+// WhereWithinBox matches rows where the geo column's bounding box overlaps the box between the two corners (index friendly &&)
+// Usage "xxx.From(`myschema.stores`).WhereWithinBox(`location`, -0.5, 51.3, 0.3, 51.7)"
+func (s *Sqlbuilder) WhereWithinBox(geoColumn string, minLon float64, minLat float64, maxLon float64, maxLat float64) *Sqlbuilder {
+	s = s.mutate()
+
+	envelope := `ST_MakeEnvelope(` + s.storeArg(minLon) + `, ` + s.storeArg(minLat) + `, ` + s.storeArg(maxLon) + `, ` + s.storeArg(maxLat) + `, ` + strconv.Itoa(geoSRID) + `)::` + s.geoCast()
+
+	return s.WhereRaw(s.geoCastColumn(geoColumn) + ` && ` + envelope)
+}
+
+// OrderByDistance orders the returned rows nearest first to the point using the index friendly <-> operator
+// Usage "xxx.From(`myschema.stores`).OrderByDistance(`location`, -0.1276, 51.5072).Limit(10)"
+func (s *Sqlbuilder) OrderByDistance(geoColumn string, lon float64, lat float64) *Sqlbuilder {
+	s = s.mutate()
+
+	bound := len(s.queryArgs)
+	s.setGeoPoint(geoColumn, lon, lat)
+	s.orderbyStmt += s.geoColumn + ` <-> ` + s.geoPoint + ` ASC, `
+	s.orderByBound(bound)
+
+	return s
+}
+
+// SelectDistance adds the ST_Distance between the geo column and point of the last WhereWithinDistance or OrderByDistance to the select
+// The point is repeated so this needs the postgres dialect
+// Usage "xxx.From(`myschema.stores`).OrderByDistance(`location`, -0.1276, 51.5072).SelectDistance(`distance`)"
+func (s *Sqlbuilder) SelectDistance(alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	if s.geoPoint == `` {
+		return s
+	}
+
+	s.reusePlaceholder(`SelectDistance`)
+
+	s.selectStmt += `ST_Distance(` + s.geoColumn + `, ` + s.geoPoint + `) AS ` + s.formatSchema(alias) + `, `
+
+	return s
+}
+
+// setGeoPoint binds the coordinates and remembers the column and point so they can be reused by SelectDistance
+func (s *Sqlbuilder) setGeoPoint(geoColumn string, lon float64, lat float64) {
+	s.geoColumn = s.geoCastColumn(geoColumn)
+	s.geoPoint = `ST_SetSRID(ST_MakePoint(` + s.storeArg(lon) + `, ` + s.storeArg(lat) + `), ` + strconv.Itoa(geoSRID) + `)::` + s.geoCast()
+}
+
+func (s *Sqlbuilder) geoCastColumn(geoColumn string) string {
+	return s.formatColumn(geoColumn) + `::` + s.geoCast()
+}
+
+func (s *Sqlbuilder) geoCast() string {
+	if s.Spatial == CastGeometry {
+		return string(CastGeometry)
+	}
+
+	return string(CastGeography)
+}
@@ -0,0 +1,69 @@
+package pqb
+
+import (
+	"testing"
+)
+
+func TestSqlbuilder_WhereWithinDistance_SelectDistance(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.stores`).
+		Select(`id`).
+		WhereWithinDistance(`location`, -0.1276, 51.5072, 5000).
+		SelectDistance(`distance`).
+		Build()
+
+	wantSql := `SELECT "id", ST_Distance("location"::geography, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography) AS "distance" FROM "myschema"."stores" WHERE ST_DWithin("location"::geography, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)`
+	wantArgs := []interface{}{-0.1276, 51.5072, float64(5000)}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereIntersects_WhereWithinBox_OrderByDistance(t *testing.T) {
+	sqlb := Sqlbuilder{Spatial: CastGeometry}
+
+	gotSql, gotArgs := sqlb.From(`myschema.stores`).
+		WhereIntersects(`boundary`, `POINT(1 2)`).
+		WhereWithinBox(`location`, -1, 50, 1, 52).
+		OrderByDistance(`location`, 0.5, 51).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."stores" WHERE ST_Intersects("boundary"::geometry, ST_GeomFromText($1, 4326)::geometry) AND "location"::geometry && ST_MakeEnvelope($2, $3, $4, $5, 4326)::geometry ORDER BY "location"::geometry <-> ST_SetSRID(ST_MakePoint($6, $7), 4326)::geometry ASC`
+	wantArgs := []interface{}{`POINT(1 2)`, float64(-1), float64(50), float64(1), float64(52), 0.5, float64(51)}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_SelectDistance_unnumbered_dialect(t *testing.T) {
+	sqlb := Sqlbuilder{Dialect: `mysql`}
+
+	gotSql, _ := sqlb.From(`stores`).OrderByDistance(`location`, -0.1276, 51.5072).SelectDistance(`distance`).Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error: got %v", gotSql)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+// WhereSimilar fuzzy match using the pg_trgm extension
+// With a threshold of 0 the index friendly % operator is used (pg_trgm.similarity_threshold applies), otherwise
+// rows must have a similarity() of at least the threshold (between 0 and 1)
+// Usage "xxx.From(`myschema.mytable`).WhereSimilar(`name`, `jon smith`, 0.4)"
+func (s *Sqlbuilder) WhereSimilar(column string, text string, threshold float64) *Sqlbuilder {
+	s = s.mutate()
+
+	if threshold <= 0 {
+		return s.WhereRaw(s.formatColumn(column) + ` % ` + s.storeArg(text))
+	}
+
+	return s.WhereRaw(`similarity(` + s.formatColumn(column) + `, ` + s.storeArg(text) + `) >= ` + s.storeArg(threshold))
+}
+
+// WhereWordSimilar fuzzy match of the text against any part of the column using the pg_trgm extension
+// With a threshold of 0 the index friendly <% operator is used (pg_trgm.word_similarity_threshold applies), otherwise
+// rows must have a word_similarity() of at least the threshold (between 0 and 1)
+// Usage "xxx.From(`myschema.mytable`).WhereWordSimilar(`address`, `baker st`, 0)"
+func (s *Sqlbuilder) WhereWordSimilar(column string, text string, threshold float64) *Sqlbuilder {
+	s = s.mutate()
+
+	if threshold <= 0 {
+		return s.WhereRaw(s.storeArg(text) + ` <% ` + s.formatColumn(column))
+	}
+
+	return s.WhereRaw(`word_similarity(` + s.storeArg(text) + `, ` + s.formatColumn(column) + `) >= ` + s.storeArg(threshold))
+}
+
+// SelectSimilarity adds the pg_trgm similarity() between the column and text to the select with the given alias
+// Usage "xxx.From(`myschema.mytable`).Select(`id`).SelectSimilarity(`name`, `jon smith`, `score`)"
+func (s *Sqlbuilder) SelectSimilarity(column string, text string, alias string) *Sqlbuilder {
+	s = s.mutate()
+
+	s.selectStmt += `similarity(` + s.formatSchema(column) + `, ` + s.storeArg(text) + `) AS ` + s.formatSchema(alias) + `, `
+
+	return s
+}
+
+// OrderBySimilarity orders the returned rows most similar first using the pg_trgm <-> distance operator (can use a GiST index)
+// Usage "xxx.From(`myschema.mytable`).OrderBySimilarity(`name`, `jon smith`).Limit(10)"
+func (s *Sqlbuilder) OrderBySimilarity(column string, text string) *Sqlbuilder {
+	s = s.mutate()
+
+	bound := len(s.queryArgs)
+	s.orderbyStmt += s.formatColumn(column) + ` <-> ` + s.storeArg(text) + ` ASC, `
+	s.orderByBound(bound)
+
+	return s
+}
@@ -0,0 +1,87 @@
+package pqb
+
+import (
+	"testing"
+)
+
+func TestSqlbuilder_WhereSimilar(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereSimilar(`name`, `jon`, 0).
+		WhereSimilar(`name`, `smith`, 0.4).
+		WhereWordSimilar(`address`, `baker st`, 0).
+		WhereWordSimilar(`address`, `london`, 0.6).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE "name" % $1 AND similarity("name", $2) >= $3 AND $4 <% "address" AND word_similarity($5, "address") >= $6`
+	wantArgs := []interface{}{`jon`, `smith`, 0.4, `baker st`, `london`, 0.6}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_SelectSimilarity_OrderBySimilarity(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		Select(`id`).
+		SelectSimilarity(`name`, `jon smith`, `score`).
+		OrderBySimilarity(`name`, `jon smith`).
+		Limit(10).
+		Build()
+
+	wantSql := `SELECT "id", similarity("name", $1) AS "score" FROM "myschema"."mytable" ORDER BY "name" <-> $2 ASC LIMIT 10`
+	wantArgs := []interface{}{`jon smith`, `jon smith`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_OrderBy_after_bound_ordering(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		OrderBySimilarity(`name`, `bob`).
+		OrderBy(`id`, `ASC`).
+		Where(`a`, `=`, `x`).
+		Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for replacing a bound ordering: got %v", gotSql)
+	}
+
+	gotSql, gotArgs := sqlb.Reset().From(`myschema.mytable`).
+		OrderBy(`id`, `ASC`).
+		OrderBy(`name`, `ASC`).
+		ThenOrderBy(`id`, `DESC`).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" ORDER BY "name" ASC, "id" DESC`
+
+	if gotSql != wantSql || len(gotArgs) != 0 {
+		t.Errorf("got %v %v \nwanted %v", gotSql, gotArgs, wantSql)
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SamuelBanksTech/Go-Postgresql-Query-Builder/pqbHelpers"
+)
+
+// Set adds a column to the SET of the next BuildUpdate, BuildUpdateMap or BuildUpdateColumns
+// The value is converted the same way as a struct field (slices become array literals, registered converters are used)
+// Usage "xxx.Where(`id`, `=`, `1`).Set(`status`, `archived`).BuildUpdate(`myschema.mytable`, nil)"
+func (s *Sqlbuilder) Set(column string, value interface{}) *Sqlbuilder {
+	s = s.mutate()
+
+	v, err := pqbHelpers.MapValue(value)
+	if err != nil {
+		s.setErr(errors.New("set: " + strconv.Quote(column) + " " + err.Error()))
+		return s
+	}
+
+	s.setStmt += s.formatSchema(column) + ` = ` + s.storeArg(v) + `, `
+
+	return s
+}
+
+// SetRaw sets a column to an unfiltered sql expression
+// WARNING do not use for user input this could pose a security risk
+// Usage "xxx.Where(`id`, `=`, `1`).SetRaw(`updated_at`, `NOW()`).BuildUpdate(`myschema.mytable`, data)"
+func (s *Sqlbuilder) SetRaw(column string, expression string) *Sqlbuilder {
+	s = s.mutate()
+
+	s.setStmt += s.formatSchema(column) + ` = ` + expression + `, `
+
+	return s
+}
+
+// Increment adds n to the current value of the column, use a negative n to decrement
+// Usage "xxx.Where(`id`, `=`, `1`).Increment(`views`, 1).BuildUpdate(`myschema.mytable`, nil)"
+func (s *Sqlbuilder) Increment(column string, n int) *Sqlbuilder {
+	s = s.mutate()
+
+	col := s.formatSchema(column)
+	s.setStmt += col + ` = ` + col + ` + ` + s.storeArg(n) + `, `
+
+	return s
+}
+
+// BuildUpdateMap updates only the columns in the map, the keys are the column names, perfect for PATCH style updates
+// The values are converted with Set
+// Usage "xxx.Where(`id`, `=`, `1`).BuildUpdateMap(`myschema.mytable`, map[string]interface{}{"name": "bob"})"
+func (s *Sqlbuilder) BuildUpdateMap(table string, values map[string]interface{}) (string, []interface{}, error) {
+
+	s = s.mutate()
+	defer s.Reset()
+
+	columns := make([]string, 0, len(values))
+	for col := range values {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+
+	for _, col := range columns {
+		s = s.Set(col, values[col])
+	}
+
+	return s.buildUpdate(table, nil)
+}
+
+// BuildUpdateColumns is BuildUpdate however only the listed columns are SET, the names are the db column names
+// "omitempty" is ignored for listed columns, "pk" fields are still used as the WHERE
+// Usage "xxx.BuildUpdateColumns(`myschema.mytable`, user, `name`, `email`)"
+func (s *Sqlbuilder) BuildUpdateColumns(table string, data interface{}, columns ...string) (string, []interface{}, error) {
+
+	s = s.mutate()
+	defer s.Reset()
+
+	fields, err := s.mapper().MapFields(data)
+	if err != nil {
+		return "", s.queryArgs, err
+	}
+
+	byColumn := make(map[string]pqbHelpers.Field, len(fields))
+	var selected []pqbHelpers.Field
+
+	for _, f := range fields {
+		byColumn[f.Column] = f
+
+		if f.PrimaryKey {
+			selected = append(selected, f)
+		}
+	}
+
+	for _, col := range columns {
+		f, ok := byColumn[`"`+col+`"`]
+
+		switch {
+		case !ok:
+			return "", s.queryArgs, errors.New("column " + col + " not found in data")
+		case f.ReadOnly || f.PrimaryKey:
+			return "", s.queryArgs, errors.New("column " + col + " can not be updated")
+		}
+
+		f.OmitEmpty = false
+		selected = append(selected, f)
+	}
+
+	return s.buildUpdate(table, selected)
+}
+
+// maxBindParams is the most bind parameters postgres accepts in a single statement
+const maxBindParams = 65535
+
+// Statement is a single query and its args, used where a build produces more than one statement
+type Statement struct {
+	Query string
+	Args  []interface{}
+}
+
+// BuildUpdateMany updates many rows each with their own values in a single UPDATE ... FROM (VALUES ...) statement
+// keyColumns are the db columns used to match each row, when empty the fields tagged "pk" are used
+// The values of the first row are cast to their postgres type (from the Go type or the "type=xxx" tag option) so the
+// VALUES list is typed correctly, rows are split into several statements if they would go over the bind parameter limit
+// Only the postgres dialect is supported
+// Any Where clauses are added to every statement, the VALUES list is aliased "v" with the same column names as the table
+// so a Where on one of the updated or key columns must be qualified with the table name or postgres reports the column
+// reference as ambiguous e.g. Where(`myschema.mytable.status`, `=`, `active`)
+// Usage "xxx.BuildUpdateMany(`myschema.mytable`, []string{`id`}, users)"
+func (s *Sqlbuilder) BuildUpdateMany(table string, keyColumns []string, data interface{}) ([]Statement, error) {
+
+	s = s.mutate()
+	defer s.Reset()
+
+	if !s.numbered() {
+		return nil, errors.New("BuildUpdateMany: UPDATE ... FROM (VALUES ...) needs the postgres dialect, not supported by the " + s.Dialect + " dialect")
+	}
+
+	rows, err := s.mapper().MapRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("data: no rows to update")
+	}
+
+	var columns []pqbHelpers.Field
+	keys := make(map[string]bool)
+
+	for _, col := range keyColumns {
+		keys[`"`+col+`"`] = true
+	}
+
+	for _, f := range rows[0] {
+		if f.ReadOnly {
+			continue
+		}
+
+		if len(keyColumns) == 0 && f.PrimaryKey {
+			keys[f.Column] = true
+		}
+
+		columns = append(columns, f)
+	}
+
+	var keyNames, setNames, colNames []string
+
+	for _, f := range columns {
+		colNames = append(colNames, f.Column)
+
+		if keys[f.Column] {
+			keyNames = append(keyNames, f.Column)
+		} else {
+			setNames = append(setNames, f.Column)
+		}
+	}
+
+	switch {
+	case len(keyNames) == 0:
+		return nil, errors.New("no key columns to match rows on")
+	case len(keyNames) != len(keys):
+		return nil, errors.New("key columns not found in data")
+	case len(setNames) == 0:
+		return nil, errors.New("no columns to update")
+	}
+
+	tableName := s.formatSchema(table)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	setString := ``
+	for _, col := range setNames {
+		setString += col + ` = "v".` + col + `, `
+	}
+
+	whereString := ``
+	for _, col := range keyNames {
+		whereString += tableName + `.` + col + ` = "v".` + col + ` AND `
+	}
+	whereString += s.whereStmt
+
+	whereArgs := s.queryArgs
+	chunkSize := (maxBindParams - len(whereArgs)) / len(columns)
+	if chunkSize < 1 {
+		return nil, errors.New("too many columns to update")
+	}
+
+	var statements []Statement
+
+	for start := 0; start < len(rows); start += chunkSize {
+		end := start + chunkSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		s.queryArgs = append([]interface{}(nil), whereArgs...)
+
+		var valueStrings []string
+
+		for i, row := range rows[start:end] {
+			rowFields := make(map[string]pqbHelpers.Field, len(row))
+			for _, f := range row {
+				rowFields[f.Column] = f
+			}
+
+			valueString := ``
+
+			for _, col := range columns {
+				valueString += s.storeArg(rowFields[col.Column].Value)
+
+				if i == 0 {
+					if cast := castFor(col, rowFields[col.Column].Value); cast != `` {
+						valueString += `::` + cast
+					}
+				}
+
+				valueString += `, `
+			}
+
+			valueStrings = append(valueStrings, `(`+strings.TrimSuffix(valueString, `, `)+`)`)
+		}
+
+		sql := `UPDATE ` + tableName + ` SET ` + strings.TrimSuffix(setString, `, `) +
+			` FROM (VALUES ` + strings.Join(valueStrings, `, `) + `) AS "v"(` + strings.Join(colNames, `, `) + `)` +
+			` WHERE ` + strings.TrimSuffix(whereString, ` AND `)
+
+		statements = append(statements, Statement{Query: sql, Args: s.queryArgs})
+	}
+
+	return statements, nil
+}
+
+// nullTypes are the postgres types of the database/sql Null types
+var nullTypes = map[reflect.Type]string{
+	reflect.TypeOf(sql.NullString{}):  `text`,
+	reflect.TypeOf(sql.NullInt16{}):   `bigint`,
+	reflect.TypeOf(sql.NullInt32{}):   `bigint`,
+	reflect.TypeOf(sql.NullInt64{}):   `bigint`,
+	reflect.TypeOf(sql.NullFloat64{}): `double precision`,
+	reflect.TypeOf(sql.NullBool{}):    `boolean`,
+	reflect.TypeOf(sql.NullTime{}):    `timestamptz`,
+}
+
+// castFor returns the postgres type to cast a column to, the "type=xxx" tag option wins over the Go type of the field
+// so a nil value is still cast, the value is only looked at when the field type says nothing (e.g. an interface{})
+func castFor(f pqbHelpers.Field, value interface{}) string {
+	if f.Type != `` {
+		return f.Type
+	}
+
+	if f.JSON {
+		// use type=json for a json rather than jsonb column
+		return `jsonb`
+	}
+
+	if cast := castForType(f.GoType); cast != `` {
+		return cast
+	}
+
+	if f.GoType != nil && (f.GoType.Kind() == reflect.Slice || f.GoType.Kind() == reflect.Array) {
+		// an array literal string of an unknown element type, left for postgres to work out from the column
+		return ``
+	}
+
+	switch value.(type) {
+	case string:
+		return `text`
+	case int64:
+		return `bigint`
+	case float64:
+		return `double precision`
+	case bool:
+		return `boolean`
+	case time.Time:
+		return `timestamptz`
+	case []byte:
+		return `bytea`
+	default:
+		return ``
+	}
+}
+
+// castForType returns the postgres type for the Go type of a field, pointers are followed, any other driver.Valuer
+// is left to the value as its Go type says nothing about what the driver receives
+func castForType(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return ``
+	}
+
+	if cast, ok := nullTypes[t]; ok {
+		return cast
+	}
+
+	if t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType) {
+		return ``
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return `text`
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return `bigint`
+	case reflect.Float32, reflect.Float64:
+		return `double precision`
+	case reflect.Bool:
+		return `boolean`
+	case reflect.Struct:
+		if t.ConvertibleTo(reflect.TypeOf(time.Time{})) {
+			return `timestamptz`
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return `bytea`
+		}
+		if cast := castForType(t.Elem()); cast != `` {
+			return cast + `[]`
+		}
+	}
+
+	return ``
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
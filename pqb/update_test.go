@@ -0,0 +1,307 @@
+package pqb
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestSqlbuilder_Set_SetRaw_Increment(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.Where(`id`, `=`, `1`).
+		Set(`status`, `archived`).
+		SetRaw(`updated_at`, `NOW()`).
+		Increment(`counter`, 1).
+		BuildUpdate(`myschema.mytable`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "status" = $2, "updated_at" = NOW(), "counter" = "counter" + $3 WHERE "id" = $1 `
+	wantArgs := []interface{}{`1`, `archived`, 1}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	gotSql, gotArgs, err = sqlb.Increment(`counter`, -2).BuildUpdate(`myschema.mytable`, mockTaggedStruct{ID: 3, Name: `bob`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql = `UPDATE "myschema"."mytable" SET "display_name" = $2, "counter" = "counter" + $1 WHERE "id" = $3 `
+	wantArgs = []interface{}{-2, `bob`, int64(3)}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMap(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.Where(`id`, `=`, `1`).
+		BuildUpdateMap(`myschema.mytable`, map[string]interface{}{`name`: `bob`, `age`: 30, `email`: nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "age" = $2, "email" = $3, "name" = $4 WHERE "id" = $1 `
+	wantArgs := []interface{}{`1`, int64(30), nil, `bob`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	if _, _, err = sqlb.BuildUpdateMap(`myschema.mytable`, nil); err == nil {
+		t.Error(`expected an error for an empty map`)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMap_mapped_values(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs, err := sqlb.Where(`id`, `=`, `1`).
+		BuildUpdateMap(`myschema.mytable`, map[string]interface{}{`tags`: []string{`a`, `b`}, `status`: mockStatus(`open`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "status" = $2, "tags" = $3 WHERE "id" = $1 `
+	wantArgs := []interface{}{`1`, `open`, `{"a","b"}`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	if _, _, err = sqlb.Where(`id`, `=`, `1`).Set(`big`, uint64(1<<63)).BuildUpdate(`myschema.mytable`, nil); err == nil {
+		t.Error(`expected an error for a uint64 that overflows int64`)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateColumns(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	data := mockTaggedStruct{ID: 7, Name: `bob`}
+
+	gotSql, gotArgs, err := sqlb.BuildUpdateColumns(`myschema.mytable`, data, `nickname`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "nickname" = $1 WHERE "id" = $2 `
+	wantArgs := []interface{}{``, int64(7)}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("argument mismatch \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for _, col := range []string{`missing`, `created_at`, `id`} {
+		if _, _, err = sqlb.BuildUpdateColumns(`myschema.mytable`, data, col); err == nil {
+			t.Errorf("expected an error for column %v", col)
+		}
+	}
+}
+
+type mockManyStruct struct {
+	ID      string `pqb:"id,pk,type=uuid"`
+	Name    string
+	Score   int
+	Created string `pqb:"created,readonly"`
+}
+
+func TestSqlbuilder_BuildUpdateMany(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	rows := []mockManyStruct{
+		{ID: `a`, Name: `bob`, Score: 1},
+		{ID: `b`, Name: `alice`, Score: 2},
+	}
+
+	gotStatements, err := sqlb.Where(`tenant_id`, `=`, `9`).BuildUpdateMany(`myschema.mytable`, nil, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatements := []Statement{{
+		Query: `UPDATE "myschema"."mytable" SET "name" = "v"."name", "score" = "v"."score" FROM (VALUES ($2::uuid, $3::text, $4::bigint), ($5, $6, $7)) AS "v"("id", "name", "score") WHERE "myschema"."mytable"."id" = "v"."id" AND "tenant_id" = $1`,
+		Args:  []interface{}{`9`, `a`, `bob`, int64(1), `b`, `alice`, int64(2)},
+	}}
+
+	if !reflect.DeepEqual(gotStatements, wantStatements) {
+		t.Errorf("got %v \nwanted %v", gotStatements, wantStatements)
+	}
+
+	gotStatements, err = sqlb.BuildUpdateMany(`mytable`, []string{`name`}, rows[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatements = []Statement{{
+		Query: `UPDATE "mytable" SET "id" = "v"."id", "score" = "v"."score" FROM (VALUES ($1::uuid, $2::text, $3::bigint)) AS "v"("id", "name", "score") WHERE "mytable"."name" = "v"."name"`,
+		Args:  []interface{}{`a`, `bob`, int64(1)},
+	}}
+
+	if !reflect.DeepEqual(gotStatements, wantStatements) {
+		t.Errorf("got %v \nwanted %v", gotStatements, wantStatements)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_chunking(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	rows := make([]mockManyStruct, maxBindParams/3+1)
+
+	gotStatements, err := sqlb.BuildUpdateMany(`mytable`, nil, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gotStatements) != 2 {
+		t.Fatalf("got %v statements wanted 2", len(gotStatements))
+	}
+
+	if len(gotStatements[0].Args) != maxBindParams || len(gotStatements[1].Args) != 3 {
+		t.Errorf("chunk args wrong: got %v and %v", len(gotStatements[0].Args), len(gotStatements[1].Args))
+	}
+
+	wantSql := `UPDATE "mytable" SET "name" = "v"."name", "score" = "v"."score" FROM (VALUES ($1::uuid, $2::text, $3::bigint)) AS "v"("id", "name", "score") WHERE "mytable"."id" = "v"."id"`
+
+	if gotStatements[1].Query != wantSql {
+		t.Errorf("got %v \nwanted %v", gotStatements[1].Query, wantSql)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_errors(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	rows := []mockManyStruct{{ID: `a`}}
+
+	if _, err := sqlb.BuildUpdateMany(`mytable`, nil, []mockManyStruct{}); err == nil {
+		t.Error(`expected an error for no rows`)
+	}
+
+	if _, err := sqlb.BuildUpdateMany(`mytable`, []string{`missing`}, rows); err == nil {
+		t.Error(`expected an error for a missing key column`)
+	}
+
+	if _, err := sqlb.BuildUpdateMany(`mytable`, nil, []mockAddress{{}}); err == nil {
+		t.Error(`expected an error for no key columns`)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_nil_first_row(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	type row struct {
+		ID    int64    `pqb:"id,pk"`
+		Score *int     `pqb:"score"`
+		Rate  *float64 `pqb:"rate"`
+		When  sql.NullTime
+	}
+
+	score := 5
+
+	gotStatements, err := sqlb.BuildUpdateMany(`mytable`, nil, []row{{ID: 1}, {ID: 2, Score: &score}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "mytable" SET "score" = "v"."score", "rate" = "v"."rate", "when" = "v"."when" FROM (VALUES ($1::bigint, $2::bigint, $3::double precision, $4::timestamptz), ($5, $6, $7, $8)) AS "v"("id", "score", "rate", "when") WHERE "mytable"."id" = "v"."id"`
+
+	if gotStatements[0].Query != wantSql {
+		t.Errorf("got %v \nwanted %v", gotStatements[0].Query, wantSql)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_qualified_where(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	rows := []mockManyStruct{{ID: `a`, Name: `bob`, Score: 1}}
+
+	gotStatements, err := sqlb.Where(`myschema.mytable.score`, `<`, `10`).BuildUpdateMany(`myschema.mytable`, nil, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "myschema"."mytable" SET "name" = "v"."name", "score" = "v"."score" FROM (VALUES ($2::uuid, $3::text, $4::bigint)) AS "v"("id", "name", "score") WHERE "myschema"."mytable"."id" = "v"."id" AND "myschema"."mytable"."score" < $1`
+
+	if gotStatements[0].Query != wantSql {
+		t.Errorf("got %v \nwanted %v", gotStatements[0].Query, wantSql)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_array_columns(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	type row struct {
+		ID   int64    `pqb:"id,pk"`
+		Tags []string `pqb:"tags"`
+		IDs  []int    `pqb:"ids"`
+	}
+
+	gotStatements, err := sqlb.BuildUpdateMany(`mytable`, nil, []row{{ID: 1, Tags: []string{`a`}}, {ID: 2, IDs: []int{3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantStatements := []Statement{{
+		Query: `UPDATE "mytable" SET "tags" = "v"."tags", "ids" = "v"."ids" FROM (VALUES ($1::bigint, $2::text[], $3::bigint[]), ($4, $5, $6)) AS "v"("id", "tags", "ids") WHERE "mytable"."id" = "v"."id"`,
+		Args:  []interface{}{int64(1), `{"a"}`, nil, int64(2), nil, `{3}`},
+	}}
+
+	if !reflect.DeepEqual(gotStatements, wantStatements) {
+		t.Errorf("got %v \nwanted %v", gotStatements, wantStatements)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_json(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	type row struct {
+		ID   int64             `pqb:"id,pk"`
+		Meta map[string]string `pqb:"meta,json"`
+		Doc  map[string]string `pqb:"doc,json,type=json"`
+	}
+
+	gotStatements, err := sqlb.BuildUpdateMany(`mytable`, nil, []row{{ID: 1, Meta: map[string]string{"a": "b"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSql := `UPDATE "mytable" SET "meta" = "v"."meta", "doc" = "v"."doc" FROM (VALUES ($1::bigint, $2::jsonb, $3::json)) AS "v"("id", "meta", "doc") WHERE "mytable"."id" = "v"."id"`
+
+	if gotStatements[0].Query != wantSql {
+		t.Errorf("got %v \nwanted %v", gotStatements[0].Query, wantSql)
+	}
+}
+
+func TestSqlbuilder_BuildUpdateMany_unnumbered_dialect(t *testing.T) {
+	sqlb := Sqlbuilder{Dialect: `mysql`}
+
+	_, err := sqlb.Where(`tenant_id`, `=`, `9`).BuildUpdateMany(`mytable`, nil, []mockManyStruct{{ID: `a`}})
+	if err == nil {
+		t.Error(`expected an error for the mysql dialect`)
+	}
+}
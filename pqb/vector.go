@@ -0,0 +1,73 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VectorMetric is the pgvector distance operator used to compare embeddings
+type VectorMetric string
+
+const (
+	// VectorL2 euclidean distance
+	VectorL2 VectorMetric = "<->"
+	// VectorCosine cosine distance
+	VectorCosine VectorMetric = "<=>"
+	// VectorInnerProduct negative inner product (pgvector negates it so smaller is still closer)
+	VectorInnerProduct VectorMetric = "<#>"
+)
+
+// OrderByVectorDistance orders the returned rows nearest first to the vector using pgvector, combine with Limit for k nearest neighbours
+// The vector is bound as a single parameter in the pgvector text format, metric defaults to VectorL2
+// Usage "xxx.From(`myschema.mytable`).OrderByVectorDistance(`embedding`, embedding, pqb.VectorCosine).Limit(5)"
+func (s *Sqlbuilder) OrderByVectorDistance(column string, vector []float32, metric VectorMetric) *Sqlbuilder {
+	s = s.mutate()
+
+	bound := len(s.queryArgs)
+	s.orderbyStmt += s.vectorDistance(column, vector, metric) + ` ASC, `
+	s.orderByBound(bound)
+
+	return s
+}
+
+// WhereVectorDistance compares the pgvector distance between the column and vector against the threshold
+// operator can be "<", "<=", ">", ">=", "=" or "!=", anything else is recorded for Err, metric defaults to VectorL2
+// Usage "xxx.From(`myschema.mytable`).WhereVectorDistance(`embedding`, embedding, pqb.VectorCosine, `<`, 0.3)"
+func (s *Sqlbuilder) WhereVectorDistance(column string, vector []float32, metric VectorMetric, operator string, threshold float64) *Sqlbuilder {
+	s = s.mutate()
+
+	return s.WhereRaw(`(` + s.vectorDistance(column, vector, metric) + `) ` + s.formatComparison(operator) + ` ` + s.storeArg(threshold))
+}
+
+func (s *Sqlbuilder) vectorDistance(column string, vector []float32, metric VectorMetric) string {
+	switch metric {
+	case VectorL2, VectorCosine, VectorInnerProduct:
+	default:
+		metric = VectorL2
+	}
+
+	return s.formatColumn(column) + ` ` + string(metric) + ` ` + s.storeArg(vectorLiteral(vector)) + `::vector`
+}
+
+// vectorLiteral serialises the slice into the pgvector text format e.g. [1,2.5,3]
+func vectorLiteral(vector []float32) string {
+	parts := make([]string, len(vector))
+	for i, v := range vector {
+		parts[i] = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+
+	return `[` + strings.Join(parts, `,`) + `]`
+}
@@ -0,0 +1,69 @@
+package pqb
+
+import (
+	"testing"
+)
+
+func TestSqlbuilder_OrderByVectorDistance(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		Select(`id`).
+		OrderByVectorDistance(`embedding`, []float32{1, 2.5, -0.125}, VectorCosine).
+		Limit(5).
+		Build()
+
+	wantSql := `SELECT "id" FROM "myschema"."mytable" ORDER BY "embedding" <=> $1::vector ASC LIMIT 5`
+	wantArgs := []interface{}{`[1,2.5,-0.125]`}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereVectorDistance(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, gotArgs := sqlb.From(`myschema.mytable`).
+		WhereVectorDistance(`embedding`, []float32{0.1, 0.2}, ``, `<`, 0.5).
+		Build()
+
+	wantSql := `SELECT * FROM "myschema"."mytable" WHERE ("embedding" <-> $1::vector) < $2`
+	wantArgs := []interface{}{`[0.1,0.2]`, 0.5}
+
+	if gotSql != wantSql {
+		t.Errorf("got %v \nwanted %v", gotSql, wantSql)
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		t.Fatalf("argument slice length wrong: \ngot %v \nwanted %v", gotArgs, wantArgs)
+	}
+
+	for i, v := range wantArgs {
+		if gotArgs[i] != v {
+			t.Errorf("argument mismatch got \ngot %v \nwanted %v as position %v", gotArgs[i], wantArgs[i], i)
+		}
+	}
+}
+
+func TestSqlbuilder_WhereVectorDistance_invalid_operator(t *testing.T) {
+	var sqlb Sqlbuilder
+
+	gotSql, _ := sqlb.From(`myschema.mytable`).
+		WhereVectorDistance(`embedding`, []float32{0.1, 0.2}, VectorCosine, `< 1 OR 1=1 OR 1 <`, 0.5).
+		Build()
+
+	if gotSql != `` || sqlb.Err() == nil {
+		t.Errorf("expected an error for an invalid operator: got %v", gotSql)
+	}
+}
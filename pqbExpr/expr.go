@@ -0,0 +1,258 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pqbExpr builds SQL expressions whose values are bound as query args, they can be passed to the
+// SelectExpr, WhereExpr, OrderByExpr, GroupByExpr and SetExpr methods of the query builder
+package pqbExpr
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Binder is implemented by the query builder, Bind stores a query arg and returns its placeholder, Ident returns a
+// correctly quoted identifier for the builder's dialect and Fail records an error so the query will not build
+type Binder interface {
+	Bind(value interface{}) string
+	Ident(identifier string) string
+	Fail(err error)
+}
+
+// funcName is an optionally schema qualified function name e.g. lower or myschema.my_func
+var funcName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// typeName is a postgres type name e.g. int, double precision, numeric(10, 2), myschema.mytype or text[]
+var typeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?( [A-Za-z_][A-Za-z0-9_]*)*(\(\d+(, ?\d+)?\))?(\[\])*$`)
+
+// Expr is a SQL expression, ToSql is called once by the query builder when the expression is added to the query
+type Expr interface {
+	ToSql(b Binder) string
+}
+
+// exprFunc allows a plain function to be used as an Expr
+type exprFunc func(b Binder) string
+
+func (f exprFunc) ToSql(b Binder) string {
+	return f(b)
+}
+
+// Col a column reference e.g. Col(`u.name`) into "u"."name"
+func Col(column string) Expr {
+	return exprFunc(func(b Binder) string {
+		return b.Ident(column)
+	})
+}
+
+// Val a value bound as a query arg
+func Val(value interface{}) Expr {
+	return exprFunc(func(b Binder) string {
+		return b.Bind(value)
+	})
+}
+
+// Raw unfiltered sql, for keywords and constants such as Raw(`NOW()`)
+// WARNING do not use for user input this could pose a security risk
+func Raw(sql string) Expr {
+	return exprFunc(func(b Binder) string {
+		return sql
+	})
+}
+
+// Func a function call e.g. Func(`lower`, Col(`email`)), a name that is not a plain (optionally schema qualified)
+// identifier is refused and the query will not build
+func Func(name string, args ...Expr) Expr {
+	return exprFunc(func(b Binder) string {
+		if !funcName.MatchString(name) {
+			b.Fail(errors.New("function: " + strconv.Quote(name) + " is not a valid function name"))
+			return ``
+		}
+
+		return name + `(` + join(b, args, `, `) + `)`
+	})
+}
+
+// Coalesce the first of the expressions that is not NULL
+func Coalesce(args ...Expr) Expr {
+	return Func(`COALESCE`, args...)
+}
+
+// Cast converts the expression to the postgres type e.g. Cast(Val(`2022-01-01`), `date`), anything other than a type
+// name (with an optional precision and array brackets) is refused and the query will not build
+func Cast(e Expr, dbType string) Expr {
+	return exprFunc(func(b Binder) string {
+		if !typeName.MatchString(dbType) {
+			b.Fail(errors.New("type: " + strconv.Quote(dbType) + " is not a valid type name"))
+			return ``
+		}
+
+		return `CAST(` + e.ToSql(b) + ` AS ` + dbType + `)`
+	})
+}
+
+// Add a + b
+func Add(a Expr, b Expr) Expr {
+	return binary(a, `+`, b)
+}
+
+// Sub a - b
+func Sub(a Expr, b Expr) Expr {
+	return binary(a, `-`, b)
+}
+
+// Mul a * b
+func Mul(a Expr, b Expr) Expr {
+	return binary(a, `*`, b)
+}
+
+// Div a / b
+func Div(a Expr, b Expr) Expr {
+	return binary(a, `/`, b)
+}
+
+// Eq a = b
+func Eq(a Expr, b Expr) Expr {
+	return binary(a, `=`, b)
+}
+
+// Ne a != b
+func Ne(a Expr, b Expr) Expr {
+	return binary(a, `!=`, b)
+}
+
+// Gt a > b
+func Gt(a Expr, b Expr) Expr {
+	return binary(a, `>`, b)
+}
+
+// Gte a >= b
+func Gte(a Expr, b Expr) Expr {
+	return binary(a, `>=`, b)
+}
+
+// Lt a < b
+func Lt(a Expr, b Expr) Expr {
+	return binary(a, `<`, b)
+}
+
+// Lte a <= b
+func Lte(a Expr, b Expr) Expr {
+	return binary(a, `<=`, b)
+}
+
+// IsNull e IS NULL
+func IsNull(e Expr) Expr {
+	return exprFunc(func(b Binder) string {
+		return `(` + e.ToSql(b) + ` IS NULL)`
+	})
+}
+
+// IsNotNull e IS NOT NULL
+func IsNotNull(e Expr) Expr {
+	return exprFunc(func(b Binder) string {
+		return `(` + e.ToSql(b) + ` IS NOT NULL)`
+	})
+}
+
+// And all of the conditions must be true, with no conditions the query will not build
+func And(conditions ...Expr) Expr {
+	return exprFunc(func(b Binder) string {
+		if len(conditions) == 0 {
+			b.Fail(errors.New("and: needs at least one condition"))
+			return ``
+		}
+
+		return `(` + join(b, conditions, ` AND `) + `)`
+	})
+}
+
+// Or any of the conditions must be true, with no conditions the query will not build
+func Or(conditions ...Expr) Expr {
+	return exprFunc(func(b Binder) string {
+		if len(conditions) == 0 {
+			b.Fail(errors.New("or: needs at least one condition"))
+			return ``
+		}
+
+		return `(` + join(b, conditions, ` OR `) + `)`
+	})
+}
+
+// Not the condition must be false
+func Not(condition Expr) Expr {
+	return exprFunc(func(b Binder) string {
+		return `NOT (` + condition.ToSql(b) + `)`
+	})
+}
+
+// CaseExpr is a CASE WHEN ... THEN ... ELSE ... END expression, build it with Case, When and Else
+type CaseExpr struct {
+	whens   [][2]Expr
+	elseVal Expr
+}
+
+// Case starts a CASE expression, at least one When is needed or the query will not build
+// Usage "pqbExpr.Case().When(pqbExpr.Gt(pqbExpr.Col(`score`), pqbExpr.Val(50)), pqbExpr.Val(`high`)).Else(pqbExpr.Val(`low`))"
+func Case() *CaseExpr {
+	return &CaseExpr{}
+}
+
+// When adds a WHEN condition THEN result
+func (c *CaseExpr) When(condition Expr, result Expr) *CaseExpr {
+	c.whens = append(c.whens, [2]Expr{condition, result})
+
+	return c
+}
+
+// Else sets the result when none of the conditions match, without it the result is NULL
+func (c *CaseExpr) Else(result Expr) *CaseExpr {
+	c.elseVal = result
+
+	return c
+}
+
+// ToSql see Expr
+func (c *CaseExpr) ToSql(b Binder) string {
+	if len(c.whens) == 0 {
+		b.Fail(errors.New("case: needs at least one When"))
+		return ``
+	}
+
+	sql := `CASE`
+
+	for _, w := range c.whens {
+		sql += ` WHEN ` + w[0].ToSql(b) + ` THEN ` + w[1].ToSql(b)
+	}
+
+	if c.elseVal != nil {
+		sql += ` ELSE ` + c.elseVal.ToSql(b)
+	}
+
+	return sql + ` END`
+}
+
+func binary(a Expr, operator string, b Expr) Expr {
+	return exprFunc(func(binder Binder) string {
+		return `(` + a.ToSql(binder) + ` ` + operator + ` ` + b.ToSql(binder) + `)`
+	})
+}
+
+func join(b Binder, exprs []Expr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = e.ToSql(b)
+	}
+
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqbHelpers
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var arrayEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// ArrayLiteral formats a slice or array as a postgres array literal e.g. []string{"go", "sql"} into {"go","sql"} so it can be
+// bound as a single string arg with any driver (database/sql drivers such as lib/pq do not accept Go slices)
+// Elements are mapped in the same way as struct fields, nil elements are NULL and nested slices are multi dimensional arrays
+func ArrayLiteral(values interface{}) (string, error) {
+	value := reflect.ValueOf(values)
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", errors.New("array: expected a slice or array, got nil")
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return "", errors.New("array: expected a slice or array, got " + value.Kind().String())
+	}
+
+	return arrayLiteral(value)
+}
+
+func arrayLiteral(value reflect.Value) (string, error) {
+	elements := make([]string, value.Len())
+
+	for i := range elements {
+		element, err := arrayElement(value.Index(i))
+		if err != nil {
+			return "", err
+		}
+
+		elements[i] = element
+	}
+
+	return "{" + strings.Join(elements, ",") + "}", nil
+}
+
+// arrayElement formats a single element of an array literal, strings are always quoted so commas, braces and NULL are safe
+func arrayElement(value reflect.Value) (string, error) {
+	nested := value
+	for (nested.Kind() == reflect.Ptr || nested.Kind() == reflect.Interface) && !nested.IsNil() {
+		nested = nested.Elem()
+	}
+
+	if isNestedArray(nested) {
+		return arrayLiteral(nested)
+	}
+
+	mapped, err := mapValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	if valuer, ok := mapped.(driver.Valuer); ok {
+		if mapped, err = valuer.Value(); err != nil {
+			return "", err
+		}
+	}
+
+	switch v := mapped.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return `"` + arrayEscaper.Replace(v) + `"`, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		if v {
+			return "t", nil
+		}
+		return "f", nil
+	case time.Time:
+		return `"` + v.Format(time.RFC3339Nano) + `"`, nil
+	case []byte:
+		return `"` + arrayEscaper.Replace(`\x`+hex.EncodeToString(v)) + `"`, nil
+	default:
+		return "", errors.New("array: element type " + value.Type().String() + " unsupported")
+	}
+}
+
+// isNestedArray reports if an array element is itself an array rather than a value (bytes are bytea and a driver.Valuer
+// or registered converter knows how to map itself)
+func isNestedArray(value reflect.Value) bool {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return false
+	}
+
+	if value.Type().Elem().Kind() == reflect.Uint8 || value.Type().Implements(valuerType) {
+		return false
+	}
+
+	_, ok := lookupConverter(value.Type())
+
+	return !ok
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqbHelpers
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+)
+
+// Converter turns a struct field value into a value that can be bound as a query arg
+type Converter func(value interface{}) (interface{}, error)
+
+var converters = struct {
+	sync.RWMutex
+	byType map[reflect.Type]Converter
+}{
+	byType: map[reflect.Type]Converter{
+		reflect.TypeOf(big.Int{}): func(value interface{}) (interface{}, error) {
+			v := value.(big.Int)
+			return v.String(), nil
+		},
+		reflect.TypeOf(big.Float{}): func(value interface{}) (interface{}, error) {
+			v := value.(big.Float)
+			return v.Text('f', -1), nil
+		},
+	},
+}
+
+// RegisterConverter registers a converter for the type of sample, it is used by MapStruct for every field of exactly that type
+// and takes priority over driver.Valuer and the built in kind mapping. Registering the same type again replaces the converter
+// Usage "pqbHelpers.RegisterConverter(money.Amount{}, func(v interface{}) (interface{}, error) { return v.(money.Amount).String(), nil })"
+func RegisterConverter(sample interface{}, convert Converter) {
+	converters.Lock()
+	defer converters.Unlock()
+
+	converters.byType[reflect.TypeOf(sample)] = convert
+}
+
+func lookupConverter(t reflect.Type) (Converter, bool) {
+	converters.RLock()
+	defer converters.RUnlock()
+
+	convert, ok := converters.byType[t]
+	return convert, ok
+}
@@ -14,8 +14,11 @@
 package pqbHelpers
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -33,12 +36,169 @@ func ToSnakeCase(str string) string {
 	return strings.ToLower(snake)
 }
 
-// MapStruct takes a struct of data and outputs two string slices (regardless of struct values)
+// Field is a single struct field mapped by MapFields along with the options set in its "pqb" tag
+type Field struct {
+	Column     string       // the quoted db column name
+	Value      interface{}  // the mapped value, see MapStruct
+	PrimaryKey bool         // tag option "pk", excluded from SET and used as the WHERE of an update
+	ReadOnly   bool         // tag option "readonly", never written by an insert or update
+	OmitEmpty  bool         // tag option "omitempty", not written when the field holds its zero value
+	Zero       bool         // the field holds its zero value
+	Type       string       // tag option "type=xxx", the postgres type used when the value needs an explicit cast e.g. uuid
+	JSON       bool         // tag option "json", the value is a JSON string
+	GoType     reflect.Type // the type of the struct field, so a cast can be chosen even when Value is nil
+}
+
+// MapStruct takes a struct of data and outputs a slice of column names and a slice of values
 // first is the db column names taken from the stuct names or overridden if the field tag "pqb" is used on the struct value
-// the second is the value in the corresponding index
-func MapStruct(data interface{}) (dbCols []string, dbVals []string, error error) {
-	fields := reflect.TypeOf(data)
-	values := reflect.ValueOf(data)
+// the second is the native value in the corresponding index (string, int64, float64, bool, time.Time or []byte) ready to be
+// bound as a query arg, nil pointers are returned as nil (NULL) and any driver.Valuer (including the database/sql Null types)
+// is returned untouched for the database driver to convert, other slices are returned as a postgres array literal string
+// (see ArrayLiteral) and a nil slice as nil
+// Fields tagged `pqb:"-"` are skipped, all other tag options are ignored (see MapFields)
+func MapStruct(data interface{}) (dbCols []string, dbVals []interface{}, error error) {
+	fields, err := MapFields(data)
+
+	for _, f := range fields {
+		dbCols = append(dbCols, f.Column)
+		dbVals = append(dbVals, f.Value)
+	}
+
+	return dbCols, dbVals, err
+}
+
+// MapFields is MapStruct with the tag options of each field, the tag format is `pqb:"name,option,option"`
+// the name can be left empty to keep the default column name e.g. `pqb:",omitempty"`
+// Options are "pk", "readonly", "omitempty", "json" (the value is stored as a JSON string for json/jsonb columns),
+// "inline" (the fields of a nested struct become columns), "prefix=xxx_" (inline with each column prefixed) and "type=xxx"
+// (the postgres type of the column, used where a cast is needed e.g. `pqb:"id,pk,type=uuid"`, a [16]byte with type=uuid is
+// bound as a uuid string rather than bytes)
+// A tag of `pqb:"-"` skips the field entirely, unexported fields are skipped and anonymous embedded structs are always inlined
+// data can be a struct or a pointer to a struct, anything else returns an error
+func MapFields(data interface{}) ([]Field, error) {
+	return Mapper{}.MapFields(data)
+}
+
+// MapRows maps a slice or array of structs (or pointers to structs) with MapFields, one entry per row
+// A single struct or pointer to a struct is returned as one row
+func MapRows(data interface{}) ([][]Field, error) {
+	return Mapper{}.MapRows(data)
+}
+
+// Mapper maps structs to columns and values using its naming strategy for any field without a "pqb" tag name
+// the zero value uses ToSnakeCase, which is what the package level MapStruct, MapFields and MapRows use
+type Mapper struct {
+	Naming NamingStrategy
+}
+
+// MapFields see the package level MapFields
+func (m Mapper) MapFields(data interface{}) ([]Field, error) {
+	value, err := structValue(reflect.ValueOf(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return m.mapFields(value, "", false, nil)
+}
+
+// MapRows see the package level MapRows
+func (m Mapper) MapRows(data interface{}) ([][]Field, error) {
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() != reflect.Struct {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		fields, err := m.MapFields(data)
+		if err != nil {
+			return nil, err
+		}
+		return [][]Field{fields}, nil
+	}
+
+	rows := make([][]Field, 0, value.Len())
+
+	for i := 0; i < value.Len(); i++ {
+		row, err := structValue(value.Index(i))
+		if err != nil {
+			return nil, errors.New("row " + strconv.Itoa(i) + ": " + err.Error())
+		}
+
+		fields, err := m.mapFields(row, "", false, nil)
+		if err != nil {
+			return nil, errors.New("row " + strconv.Itoa(i) + ": " + err.Error())
+		}
+
+		rows = append(rows, fields)
+	}
+
+	return rows, nil
+}
+
+// structValue dereferences pointers and interfaces and checks that a struct was found
+func structValue(value reflect.Value) (reflect.Value, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return value, errors.New("data: expected a struct or pointer to a struct, got nil " + value.Type().String())
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		if !value.IsValid() {
+			return value, errors.New("data: expected a struct or pointer to a struct, got nil")
+		}
+		return value, errors.New("data: expected a struct or pointer to a struct, got " + value.Type().String())
+	}
+
+	return value, nil
+}
+
+// fieldTag is a parsed "pqb" field tag
+type fieldTag struct {
+	name      string
+	pk        bool
+	readonly  bool
+	omitempty bool
+	json      bool
+	inline    bool
+	prefix    string
+	dbType    string
+}
+
+func parseTag(tag string) fieldTag {
+	tagParts := strings.Split(tag, ",")
+	ft := fieldTag{name: tagParts[0]}
+
+	for _, opt := range tagParts[1:] {
+		opt = strings.TrimSpace(opt)
+
+		switch {
+		case opt == "pk":
+			ft.pk = true
+		case opt == "readonly":
+			ft.readonly = true
+		case opt == "omitempty":
+			ft.omitempty = true
+		case opt == "json":
+			ft.json = true
+		case opt == "inline":
+			ft.inline = true
+		case strings.HasPrefix(opt, "type="):
+			ft.dbType = strings.TrimPrefix(opt, "type=")
+		case strings.HasPrefix(opt, "prefix="):
+			ft.inline = true
+			ft.prefix = strings.TrimPrefix(opt, "prefix=")
+		}
+	}
+
+	return ft
+}
+
+// mapFields walks the fields of the struct, nested structs that are inlined are walked with the column prefix and
+// readonly option of their parent field
+func (m Mapper) mapFields(values reflect.Value, prefix string, readonly bool, mapped []Field) ([]Field, error) {
+	fields := values.Type()
 
 	num := fields.NumField()
 
@@ -46,49 +206,198 @@ func MapStruct(data interface{}) (dbCols []string, dbVals []string, error error)
 		field := fields.Field(i)
 		value := values.Field(i)
 
-		val, exists := field.Tag.Lookup("pqb")
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag, _ := field.Tag.Lookup("pqb")
+		if tag == "-" {
+			continue
+		}
+
+		ft := parseTag(tag)
 
-		if exists {
-			dbCols = append(dbCols, "\""+val+"\"")
-		} else {
-			dbCols = append(dbCols, "\""+ToSnakeCase(field.Name)+"\"")
-		}
-
-		var v string
-
-		switch value.Type().String() {
-		case "string":
-			v = "'" + SanitiseString(value.String()) + "'"
-		case "int":
-			v = strconv.FormatInt(value.Int(), 10)
-		case "int8":
-			v = strconv.FormatInt(value.Int(), 10)
-		case "int32":
-			v = strconv.FormatInt(value.Int(), 10)
-		case "int64":
-			v = strconv.FormatInt(value.Int(), 10)
-		case "float64":
-			v = fmt.Sprintf("%f", value.Float())
-		case "float32":
-			v = fmt.Sprintf("%f", value.Float())
-		case "time.Time":
-			tr := value.Interface()
-			t := tr.(time.Time)
-			v = "'" + t.Format("2006-01-02 15:04:05") + "'"
-		case "bool":
-			if value.Bool() {
-				v = "TRUE"
-			} else {
-				v = "FALSE"
+		if !ft.json && (ft.inline || (field.Anonymous && ft.name == "")) && isInlineStruct(value.Type()) {
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
 			}
-		default:
-			return dbCols, dbVals, errors.New("type: " + value.Kind().String() + " unsupported")
+
+			var err error
+			mapped, err = m.mapFields(value, prefix+ft.prefix, readonly || ft.readonly, mapped)
+			if err != nil {
+				return mapped, err
+			}
+
+			continue
 		}
 
-		dbVals = append(dbVals, v)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		f := Field{
+			PrimaryKey: ft.pk,
+			ReadOnly:   readonly || ft.readonly,
+			OmitEmpty:  ft.omitempty,
+			Type:       ft.dbType,
+			JSON:       ft.json,
+			GoType:     field.Type,
+			Zero:       value.IsZero(),
+		}
+
+		if ft.name != "" {
+			f.Column = "\"" + prefix + ft.name + "\""
+		} else {
+			f.Column = "\"" + prefix + m.columnName(field.Name) + "\""
+		}
+
+		var v interface{}
+		var err error
+
+		if ft.json {
+			v, err = mapJSON(value)
+		} else {
+			v, err = mapValue(value)
+		}
+		if err != nil {
+			return mapped, err
+		}
+
+		// 16 bytes are only a uuid when the field says so, otherwise they are bound as bytea
+		if b, ok := v.([]byte); ok && len(b) == 16 && strings.EqualFold(ft.dbType, "uuid") {
+			v = formatUUID(b)
+		}
+
+		f.Value = v
+		mapped = append(mapped, f)
 	}
 
-	return dbCols, dbVals, nil
+	return mapped, nil
+}
+
+func (m Mapper) columnName(fieldName string) string {
+	if m.Naming == nil {
+		return ToSnakeCase(fieldName)
+	}
+
+	return m.Naming.ColumnName(fieldName)
+}
+
+// isInlineStruct reports if the type is a struct (or pointer to one) that should be walked rather than mapped as a single value
+func isInlineStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if _, ok := lookupConverter(t); ok {
+		return false
+	}
+
+	return t.Kind() == reflect.Struct && !t.ConvertibleTo(timeType) && !t.Implements(valuerType) && !reflect.PtrTo(t).Implements(valuerType)
+}
+
+// mapJSON marshals the value as a JSON string, nil pointers, maps and slices are returned as nil (NULL) rather than "null"
+func mapJSON(value reflect.Value) (interface{}, error) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			return nil, nil
+		}
+	}
+
+	b, err := json.Marshal(value.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+
+// MapValue converts a single value the same way MapFields converts a struct field (registered converters, driver.Valuer,
+// named types, slices as array literals), nil is returned as nil
+func MapValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return mapValue(reflect.ValueOf(value))
+}
+
+// mapValue converts a single struct value, pointers and interfaces are followed until a supported value or nil is found
+// Registered converters are checked first, then driver.Valuer, then the value is mapped by its kind so named types such as
+// `type Status string` are supported without any extra code
+func mapValue(value reflect.Value) (interface{}, error) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if convert, ok := lookupConverter(value.Type()); ok {
+		return convert(value.Interface())
+	}
+
+	if value.Type().Implements(valuerType) {
+		return value.Interface(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return mapValue(value.Elem())
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := value.Uint()
+		if u > math.MaxInt64 {
+			return nil, errors.New("type: " + value.Type().String() + " value " + strconv.FormatUint(u, 10) + " overflows int64")
+		}
+		return int64(u), nil
+	case reflect.Float32:
+		// go via the shortest decimal representation so 1.1 stays 1.1 rather than 1.100000023841858
+		return strconv.ParseFloat(strconv.FormatFloat(value.Float(), 'g', -1, 32), 64)
+	case reflect.Float64:
+		return value.Float(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Struct:
+		if value.Type().ConvertibleTo(timeType) {
+			return value.Convert(timeType).Interface(), nil
+		}
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Bytes(), nil
+		}
+		if value.IsNil() {
+			return nil, nil
+		}
+		// other slices are bound as a postgres array literal so any driver accepts them
+		return arrayLiteral(value)
+	case reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(b), value)
+			return b, nil
+		}
+		return arrayLiteral(value)
+	}
+
+	return nil, errors.New("type: " + value.Type().String() + " unsupported")
+}
+
+// formatUUID formats 16 bytes in the canonical uuid format e.g. 123e4567-e89b-12d3-a456-426614174000
+func formatUUID(b []byte) string {
+	h := hex.EncodeToString(b)
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
 }
 
 // SanitiseString this is the first step in adding some much needed security
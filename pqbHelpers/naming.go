@@ -0,0 +1,144 @@
+// Copyright 2022 SamuelBanksTech. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pqbHelpers
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy converts a struct field name into a db column name, it is only used for fields without a "pqb" tag name
+type NamingStrategy interface {
+	ColumnName(fieldName string) string
+}
+
+// NamingFunc allows a plain function to be used as a NamingStrategy
+type NamingFunc func(fieldName string) string
+
+// ColumnName calls the function
+func (f NamingFunc) ColumnName(fieldName string) string {
+	return f(fieldName)
+}
+
+// SnakeCase e.g. HTTPStatusCode into http_status_code
+// Acronyms are kept as a single word (including a trailing plural s) e.g. UserIDs into user_ids with Acronyms: []string{"ID"}
+// with no acronyms this is the same as ToSnakeCase
+type SnakeCase struct {
+	Acronyms []string
+}
+
+// ColumnName see SnakeCase
+func (n SnakeCase) ColumnName(fieldName string) string {
+	if len(n.Acronyms) == 0 {
+		return ToSnakeCase(fieldName)
+	}
+
+	words := SplitWords(fieldName, n.Acronyms)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+
+	return strings.Join(words, "_")
+}
+
+// CamelCase lower camel case e.g. HTTPStatusCode into httpStatusCode and UserIDs into userIds with Acronyms: []string{"ID"}
+type CamelCase struct {
+	Acronyms []string
+}
+
+// ColumnName see CamelCase
+func (n CamelCase) ColumnName(fieldName string) string {
+	words := SplitWords(fieldName, n.Acronyms)
+
+	for i, w := range words {
+		r := []rune(strings.ToLower(w))
+		if i > 0 && len(r) > 0 {
+			r[0] = unicode.ToUpper(r[0])
+		}
+		words[i] = string(r)
+	}
+
+	return strings.Join(words, "")
+}
+
+// LowerCase the field name lower cased with no separators e.g. HTTPStatusCode into httpstatuscode
+type LowerCase struct{}
+
+// ColumnName see LowerCase
+func (LowerCase) ColumnName(fieldName string) string {
+	return strings.ToLower(fieldName)
+}
+
+// SplitWords splits a Go field name into its words e.g. HTTPServerURL into HTTP, Server, URL
+// Any of the acronyms found are always kept as one word, optionally followed by a plural s e.g. IDs
+func SplitWords(name string, acronyms []string) []string {
+	acronyms = append([]string(nil), acronyms...)
+	sort.Slice(acronyms, func(i, j int) bool { return len(acronyms[i]) > len(acronyms[j]) })
+
+	runes := []rune(name)
+
+	var words []string
+
+	for i := 0; i < len(runes); {
+		if end := acronymAt(runes, i, acronyms); end > i {
+			words = append(words, string(runes[i:end]))
+			i = end
+			continue
+		}
+
+		j := i + 1
+
+		if unicode.IsUpper(runes[i]) && j < len(runes) && unicode.IsUpper(runes[j]) {
+			// a run of capitals, the last capital belongs to the next word if it is followed by a lower case letter
+			// acronyms are only matched at the start of a word so PAID stays one word with Acronyms: []string{"ID"}
+			for j < len(runes) && (unicode.IsUpper(runes[j]) || unicode.IsDigit(runes[j])) {
+				if j+1 < len(runes) && unicode.IsLower(runes[j+1]) {
+					break
+				}
+				j++
+			}
+		} else {
+			for j < len(runes) && (unicode.IsLower(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+		}
+
+		words = append(words, string(runes[i:j]))
+		i = j
+	}
+
+	return words
+}
+
+// acronymAt returns the end of the acronym starting at rune i or i if there is not one
+func acronymAt(runes []rune, i int, acronyms []string) int {
+	for _, acronym := range acronyms {
+		a := []rune(acronym)
+		if len(a) == 0 || len(a) > len(runes)-i || string(runes[i:i+len(a)]) != acronym {
+			continue
+		}
+
+		end := i + len(a)
+		if end < len(runes) && runes[end] == 's' && (end+1 == len(runes) || !unicode.IsLower(runes[end+1])) {
+			end++
+		}
+
+		if end == len(runes) || !unicode.IsLower(runes[end]) {
+			return end
+		}
+	}
+
+	return i
+}